@@ -0,0 +1,73 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// AdaptiveRate is how many messages per second a VAdaptive call site may write
+// at its base level.
+// Each further factor of ten in its rate requires one more level of verbosity.
+const AdaptiveRate = 10
+
+// siteRates counts calls per call site, in one-second windows.
+type siteRates struct {
+	mu    sync.Mutex
+	sites map[uintptr]*siteRate
+}
+
+type siteRate struct {
+	window time.Time // Start of the current window.
+	count  int       // Calls in the window so far.
+}
+
+// Counts a call from the site at pc, returning how many levels of verbosity the
+// site's rate this second adds.
+func (s *siteRates) penalty(pc uintptr) int {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sites == nil {
+		s.sites = make(map[uintptr]*siteRate)
+	}
+	r := s.sites[pc]
+	if r == nil {
+		r = new(siteRate)
+		s.sites[pc] = r
+	}
+	if now.Sub(r.window) >= time.Second {
+		r.window, r.count = now, 0
+	}
+	r.count++
+	extra := 0
+	for n := AdaptiveRate; r.count > n; n *= 10 {
+		extra++
+	}
+	return extra
+}
+
+// VAdaptive writes log messages at INFO level, like V, except that a call site
+// firing more than AdaptiveRate times a second needs more verbosity: one level
+// more for each factor of ten over that rate.
+// This keeps a hot loop from dominating the log, unless the verbosity is
+// cranked up to see it.
+func (l *Logger) VAdaptive(baseLevel int, format string, v ...interface{}) {
+	vAdaptive(l, baseLevel, format, v...)
+}
+
+// VAdaptive writes log messages at INFO level to the root logger, like V,
+// except that a call site firing very frequently needs more verbosity.
+// See Logger.VAdaptive for details.
+func VAdaptive(baseLevel int, format string, v ...interface{}) {
+	vAdaptive(Root, baseLevel, format, v...)
+}
+
+// Shared by both forms of VAdaptive, so the call depth is the same for each.
+func vAdaptive(l *Logger, baseLevel int, format string, v ...interface{}) {
+	var pc [1]uintptr
+	runtime.Callers(3, pc[:])
+	if l.LoudEnough(baseLevel + l.shared.sites.penalty(pc[0])) {
+		l.write(LevelInfo, l.i, l.calldepth+1, format, v...)
+	}
+}
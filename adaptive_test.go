@@ -0,0 +1,36 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVAdaptive(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestVAdaptive")
+	lg.Info = il
+	lg.SetVerbosity(1)
+
+	for i := 0; i < 100; i++ {
+		lg.VAdaptive(1, "Hot")
+	}
+	lg.VAdaptive(1, "Cold")
+
+	// The window may roll over during the loop, letting through another batch.
+	if got := strings.Count(il.String(), "Hot\n"); got < AdaptiveRate || got > 2*AdaptiveRate {
+		t.Errorf("Got %v hot messages, want %v to %v", got, AdaptiveRate, 2*AdaptiveRate)
+	}
+	if got := strings.Count(il.String(), "Cold\n"); got != 1 {
+		t.Errorf("Got %v cold messages, want 1", got)
+	}
+
+	il.Reset()
+	lg.SetVerbosity(3)
+	for i := 0; i < 100; i++ {
+		lg.VAdaptive(1, "Hot")
+	}
+	if got := strings.Count(il.String(), "Hot\n"); got != 100 {
+		t.Errorf("Got %v hot messages with the verbosity cranked up, want 100", got)
+	}
+}
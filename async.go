@@ -0,0 +1,150 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned by writes to a closed AsyncWriter.
+var ErrClosed = errors.New("log: write to closed writer")
+
+// DefaultAsyncBuffer is the number of writes an AsyncWriter from
+// NewAsyncWriter can hold before it is full.
+const DefaultAsyncBuffer = 1024
+
+// AsyncWriter is an io.Writer that hands writes to a background goroutine,
+// which writes them to the real writer, so that logging does not wait on a slow
+// destination (like a network syslog) until its buffer fills.
+// When full, it drops writes (counting them; see Dropped), or with SetBlocking,
+// waits for room.
+//
+// Writes reach the real writer in the order they were made. Errors from the
+// real writer are returned by the next Flush.
+// Loggers flush their AsyncWriters before calling Exit for a Fatal message.
+// An AsyncWriter is safe for concurrent use.
+type AsyncWriter struct {
+	w     io.Writer
+	ch    chan asyncItem
+	block int32 // Accessed atomically.
+
+	dropped int64 // Accessed atomically.
+
+	mu     sync.RWMutex // Guards closed, so ch is not written once closed.
+	closed bool
+	done   chan struct{}
+
+	errMu sync.Mutex
+	err   error
+}
+
+// A write, or (with flushed set) a request to signal once everything before it
+// has been written.
+type asyncItem struct {
+	p       []byte
+	flushed chan struct{}
+}
+
+// NewAsyncWriter returns an AsyncWriter for w with a buffer of size writes, or
+// DefaultAsyncBuffer if size is zero or less.
+func NewAsyncWriter(w io.Writer, size int) *AsyncWriter {
+	if size <= 0 {
+		size = DefaultAsyncBuffer
+	}
+	a := &AsyncWriter{
+		w:    w,
+		ch:   make(chan asyncItem, size),
+		done: make(chan struct{}),
+	}
+	go a.drain()
+	return a
+}
+
+func (a *AsyncWriter) drain() {
+	defer close(a.done)
+	for item := range a.ch {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		if _, err := a.w.Write(item.p); err != nil {
+			a.errMu.Lock()
+			if a.err == nil {
+				a.err = err
+			}
+			a.errMu.Unlock()
+		}
+	}
+}
+
+// SetBlocking sets whether writes wait for room when the buffer is full,
+// rather than being dropped (the default).
+func (a *AsyncWriter) SetBlocking(block bool) {
+	var v int32
+	if block {
+		v = 1
+	}
+	atomic.StoreInt32(&a.block, v)
+}
+
+// Dropped returns how many writes were dropped because the buffer was full.
+func (a *AsyncWriter) Dropped() int {
+	return int(atomic.LoadInt64(&a.dropped))
+}
+
+// Write queues a copy of p to be written.
+// It always reports success unless the writer is closed, even if p is
+// dropped.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return 0, ErrClosed
+	}
+	item := asyncItem{p: append([]byte(nil), p...)}
+	if atomic.LoadInt32(&a.block) != 0 {
+		a.ch <- item
+		return len(p), nil
+	}
+	select {
+	case a.ch <- item:
+	default:
+		atomic.AddInt64(&a.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Flush waits until everything already written has reached the real writer,
+// and returns the first error the real writer has returned since the last
+// Flush, if any.
+func (a *AsyncWriter) Flush() error {
+	a.mu.RLock()
+	if !a.closed {
+		flushed := make(chan struct{})
+		a.ch <- asyncItem{flushed: flushed}
+		a.mu.RUnlock()
+		<-flushed
+	} else {
+		a.mu.RUnlock()
+		<-a.done
+	}
+	a.errMu.Lock()
+	defer a.errMu.Unlock()
+	err := a.err
+	a.err = nil
+	return err
+}
+
+// Close writes everything already written to the real writer, and stops the
+// background goroutine.
+// The real writer is not closed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.ch)
+	}
+	a.mu.Unlock()
+	return a.Flush()
+}
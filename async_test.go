@@ -0,0 +1,62 @@
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+// gatedWriter blocks each write until it is let through.
+type gatedWriter struct {
+	lockedBuffer
+	gate chan struct{}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	return g.lockedBuffer.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	gw := &gatedWriter{gate: make(chan struct{})}
+	aw := NewAsyncWriter(gw, 2)
+	lg := New("TestAsyncWriter")
+	lg.Info = aw
+	lg.Fatal = aw
+	exited := false
+	lg.Exit = func() {
+		exited = true
+		if s := gw.String(); !strings.Contains(s, "Fatal message") {
+			t.Errorf("Got %v at Exit, want the fatal message already written", s)
+		}
+	}
+
+	// The first write is taken by the goroutine and blocks there, the next two
+	// fill the buffer, and the rest are dropped without blocking.
+	for i := 0; i < 10; i++ {
+		lg.Infof("Test message")
+	}
+	if got := aw.Dropped(); got < 7 {
+		t.Errorf("Got %v dropped writes, want at least 7", got)
+	}
+
+	close(gw.gate)
+	aw.SetBlocking(true)
+	lg.Fatalf("Fatal message")
+	if !exited {
+		t.Errorf("Got no call to Exit, want one")
+	}
+	if err := aw.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, err := aw.Write([]byte("x")); err != ErrClosed {
+		t.Errorf("Got %v from Write after Close, want ErrClosed", err)
+	}
+
+	s := gw.String()
+	if got := strings.Count(s, "Test message\n") + aw.Dropped(); got != 10 {
+		t.Errorf("Got %v messages written or dropped, want 10", got)
+	}
+	if i, j := strings.LastIndex(s, "Test message"), strings.Index(s, "Fatal message"); i > j {
+		t.Errorf("Got %v, want messages in the order written", s)
+	}
+}
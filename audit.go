@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// auditRequired lists the fields every audit event must carry.
+var auditRequired = []string{"actor", "action", "result"}
+
+// Audit writes an audit event to the AuditTrail writer.
+// The action is recorded as the "action" field, and fields must supply at least
+// "actor" and "result".
+// If any required field is missing, the event is still written (so nothing is
+// lost from the trail), a warning is logged, and an error naming the missing
+// fields is returned.
+func (l *Logger) Audit(action string, fields Fields) error {
+	return audit(l, action, fields)
+}
+
+// Audit writes an audit event to the root logger's AuditTrail writer.
+// See Logger.Audit for details.
+func Audit(action string, fields Fields) error {
+	return audit(Root, action, fields)
+}
+
+// Shared by both forms of Audit, so the call depth is the same for each.
+func audit(l *Logger, action string, fields Fields) error {
+	f := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		f[k] = v
+	}
+	f["action"] = action
+
+	l.writeFields(levelAudit, l.a, l.calldepth+1, f, "")
+
+	var missing []string
+	for _, k := range auditRequired {
+		if v, ok := f[k]; !ok || v == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("audit event %q is missing required fields: %s", action, strings.Join(missing, ", "))
+	l.write(LevelWarn, l.w, l.calldepth+1, "%v", err)
+	return err
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	il, wl, al := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestAudit")
+	lg.Info = il
+	lg.Warn = wl
+	lg.AuditTrail = al
+
+	if err := lg.Audit("login", Fields{"actor": "alice", "result": "ok"}); err != nil {
+		t.Errorf("Got error %v, want nil for a complete audit event", err)
+	}
+	m := regexp.MustCompile("^A.*audit_test.go:\\d+: action=login actor=alice result=ok\n$")
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log", s)
+	}
+	if s := wl.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from warn log", s)
+	}
+
+	al.Truncate(0)
+	if err := lg.Audit("logout", Fields{"actor": "alice"}); err == nil {
+		t.Errorf("Got nil error, want one for an audit event missing its result")
+	}
+	m = regexp.MustCompile("^A.*: action=logout actor=alice\n$")
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+	m = regexp.MustCompile("^W.*audit_test.go:\\d+: .*missing required fields: result\n$")
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+}
+
+func TestAuditFormatted(t *testing.T) {
+	al := new(bytes.Buffer)
+	lg := New("TestAuditFormatted")
+	lg.AuditTrail = al
+	lg.MaxFields = 1
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.Audit("login", Fields{"actor": "alice", "result": "ok"})
+
+	var got struct {
+		Level  string
+		Msg    string
+		Fields map[string]interface{}
+	}
+	if err := json.Unmarshal(al.Bytes(), &got); err != nil {
+		t.Fatalf("Got %v, want JSON from audit log: %v", al.String(), err)
+	}
+	if got.Msg != "" || got.Fields["action"] != "login" || got.Fields["actor"] != "alice" || got.Fields["result"] != "ok" {
+		t.Errorf("Got %+v, want an empty message with all three fields, despite MaxFields", got)
+	}
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// verbosityBoost tracks a temporary raise of a Logger's verbosity.
+type verbosityBoost struct {
+	// The boosted verbosity plus one, or zero when not boosted.
+	// Accessed atomically, since it is read on every V call.
+	level int64
+
+	mu  sync.Mutex
+	gen int // Identifies the current timer, so a stale one cannot end a newer boost.
+	t   *time.Timer
+}
+
+func (b *verbosityBoost) covers(level int) bool {
+	v := atomic.LoadInt64(&b.level)
+	return v != 0 && int64(level) <= v-1
+}
+
+// BoostVerbosity raises the logger's verbosity to level for the duration d,
+// after which its own Verbosity applies again.
+// Invoking it again replaces the level and restarts the timer.
+// This is meant for admin actions like "debug for the next 5 minutes".
+//
+// The logger's Verbosity is left untouched; the boost only ever makes the
+// logger louder.
+func (l *Logger) BoostVerbosity(level int, d time.Duration) {
+	b := &l.shared.boost
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.t != nil {
+		b.t.Stop()
+	}
+	b.gen++
+	gen := b.gen
+	atomic.StoreInt64(&b.level, int64(level)+1)
+	b.t = time.AfterFunc(d, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.gen == gen {
+			atomic.StoreInt64(&b.level, 0)
+			b.t = nil
+		}
+	})
+}
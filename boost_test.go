@@ -0,0 +1,37 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoostVerbosity(t *testing.T) {
+	lg := New("TestBoostVerbosity")
+	lg.SetVerbosity(1)
+
+	lg.BoostVerbosity(5, 20*time.Millisecond)
+	if !lg.LoudEnough(5) {
+		t.Errorf("Expected a boost to 5 to be loud enough for level 5")
+	}
+	// Re-boosting restarts the timer.
+	time.Sleep(10 * time.Millisecond)
+	lg.BoostVerbosity(4, 50*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if !lg.LoudEnough(4) {
+		t.Errorf("Expected the second boost to 4 to still be in effect")
+	}
+	if lg.LoudEnough(5) {
+		t.Errorf("Expected the second boost to replace the first")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for lg.LoudEnough(2) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if lg.LoudEnough(2) {
+		t.Errorf("Expected verbosity to be restored to 1 after the boost expired")
+	}
+	if !lg.LoudEnough(1) {
+		t.Errorf("Expected the restored verbosity of 1 to be loud enough for level 1")
+	}
+}
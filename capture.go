@@ -0,0 +1,87 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent writes.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.String()
+}
+
+// CaptureString runs fn with all of the logger's writers (including
+// AuditTrail) redirected to a buffer, and returns everything logged.
+// The writers are restored afterward, even if fn panics.
+// This is meant for quick assertions in tests; it has no effect on loggers from
+// NewTest, and the writers should not be changed elsewhere while it runs.
+func (l *Logger) CaptureString(fn func()) string {
+	buf := new(lockedBuffer)
+	defer l.redirect(buf)()
+	fn()
+	return buf.String()
+}
+
+// Redirects all of the logger's writers to w, returning a function that
+// restores them.
+func (l *Logger) redirect(w io.Writer) (restore func()) {
+	o := l.own()
+	ws := []*io.Writer{&o.Debug, &o.Info, &o.Warn, &o.Error, &o.Fatal, &o.AuditTrail}
+	saved := make([]io.Writer, len(ws))
+	l.shared.writersMu.Lock()
+	for i, p := range ws {
+		saved[i] = *p
+		*p = w
+	}
+	l.shared.writersMu.Unlock()
+	return func() {
+		l.shared.writersMu.Lock()
+		defer l.shared.writersMu.Unlock()
+		for i, p := range ws {
+			*p = saved[i]
+		}
+	}
+}
+
+// failureReporter is implemented by *testing.T and *testing.B.
+type failureReporter interface {
+	Failed() bool
+	Cleanup(func())
+}
+
+// OnTestFailure captures everything the logger writes from now until t's test
+// ends, then writes it to t's log only if the test failed.
+// This keeps passing tests quiet and failing tests informative.
+//
+// t must also have the Failed and Cleanup methods of *testing.T; otherwise
+// (and for loggers from NewTest, whose output already goes to a test) nothing
+// changes.
+// The writers should not be changed elsewhere until the test ends.
+func (l *Logger) OnTestFailure(t TestLogable) {
+	r, ok := t.(failureReporter)
+	if !ok {
+		return
+	}
+	buf := new(lockedBuffer)
+	restore := l.redirect(buf)
+	r.Cleanup(func() {
+		restore()
+		if r.Failed() {
+			t.Logf("Logs from %s:\n%s", l.name, buf.String())
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestCaptureString(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestCaptureString")
+	lg.Info = il
+
+	s := lg.CaptureString(func() {
+		lg.Infof("Test message")
+		lg.Errorf("Test message")
+	})
+	m := regexp.MustCompile(`^I.*capture_test.go:\d+: Test message
+E.*capture_test.go:\d+: Test message
+$`)
+	if !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from CaptureString", s, m)
+	}
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		lg.CaptureString(func() {
+			panic("Test panic")
+		})
+	}()
+	if lg.Info != il {
+		t.Errorf("Got info writer %v, want the original restored after a panic", lg.Info)
+	}
+	lg.Infof("Test message")
+	if s := il.String(); !imatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from restored info log", s, imatcher)
+	}
+}
+
+// failingTest is a fakeTest that can fail, and runs its cleanups on demand.
+type failingTest struct {
+	fakeTest
+	failed   bool
+	cleanups []func()
+}
+
+func (f *failingTest) Failed() bool      { return f.failed }
+func (f *failingTest) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *failingTest) end() {
+	for _, fn := range f.cleanups {
+		fn()
+	}
+}
+
+func TestOnTestFailure(t *testing.T) {
+	for _, failed := range []bool{false, true} {
+		il := new(bytes.Buffer)
+		lg := New("TestOnTestFailure")
+		lg.Info = il
+		ft := &failingTest{fakeTest: fakeTest{info: new(bytes.Buffer)}}
+
+		lg.OnTestFailure(ft)
+		lg.Infof("Test message")
+		ft.failed = failed
+		ft.end()
+
+		if s := il.String(); len(s) > 0 {
+			t.Errorf("Got %v, want empty from info log while captured", s)
+		}
+		m := regexp.MustCompile(`^Logs from TestOnTestFailure:
+I.*capture_test.go:\d+: Test message
+$`)
+		if s := ft.info.String(); failed != m.MatchString(s) {
+			t.Errorf("Got %v from the test log of a test with failed=%v, want a dump only on failure", s, failed)
+		}
+
+		lg.Infof("Test message")
+		if s := il.String(); !imatcher.MatchString(s) {
+			t.Errorf("Got %v, want something matching %v from restored info log", s, imatcher)
+		}
+	}
+}
+
+func TestCaptureStringDerived(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestCaptureStringDerived")
+	lg.Info = il
+	c, _ := lg.WithFields(Fields{"user": "ann"}).Indent()
+
+	s := c.CaptureString(func() {
+		c.Infof("Test message")
+	})
+	m := regexp.MustCompile(`^I.*capture_test.go:\d+:   Test message user=ann
+$`)
+	if !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from CaptureString", s, m)
+	}
+	if il.Len() != 0 {
+		t.Errorf("Got %v from info log, want nothing while captured", il)
+	}
+}
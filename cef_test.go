@@ -0,0 +1,28 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestCEFFormatter(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestCEFFormatter")
+	lg.Error = el
+	cef := NewCEFFormatter()
+	cef.SetCEFVendor("Acme|Corp")
+	cef.SetCEFProduct("billing")
+	cef.SetCEFVersion("2.1")
+	lg.SetFormatter(cef)
+
+	lg.WithFields(Fields{
+		"query":     `a=b\c`,
+		"user.name": "ann",
+	}).Errorf("Charge failed | retrying")
+
+	m := regexp.MustCompile(`^CEF:0\|Acme\\\|Corp\|billing\|2\.1\|[0-9a-f]{16}\|Charge failed \\\| retrying\|8\|rt=\d+ query=a\\=b\\\\c user_name=ann\n$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// ANSI escape codes coloring each level's letter, for Color.
+var levelColors = [numLevels]string{
+	LevelDebug: "\x1b[90m",   // Gray.
+	LevelInfo:  "\x1b[37m",   // White.
+	LevelWarn:  "\x1b[33m",   // Yellow.
+	LevelError: "\x1b[31m",   // Red.
+	LevelFatal: "\x1b[1;31m", // Bold red.
+}
+
+const colorReset = "\x1b[0m"
+
+// Whether each file is a terminal, as found by isTerminal.
+var terminals sync.Map // *os.File -> bool
+
+// Reports whether w is a terminal. Replaced in tests.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	if t, ok := terminals.Load(f); ok {
+		return t.(bool)
+	}
+	fi, err := f.Stat()
+	t := err == nil && fi.Mode()&os.ModeCharDevice != 0
+	terminals.Store(f, t)
+	return t
+}
+
+// Returns prefix wrapped in the color for level, if std writes to a terminal
+// and NO_COLOR is not set; otherwise prefix unchanged.
+func colorPrefix(std *log.Logger, level Level, prefix string) string {
+	if prefix == "" || levelColors[level] == "" || os.Getenv("NO_COLOR") != "" {
+		return prefix
+	}
+	w := std.Writer()
+	if rw, ok := w.(*rewriter); ok {
+		w = rw.dest()
+	}
+	if !isTerminal(w) {
+		return prefix
+	}
+	return levelColors[level] + prefix + colorReset
+}
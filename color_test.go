@@ -0,0 +1,37 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	term, plain := new(bytes.Buffer), new(bytes.Buffer)
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(w io.Writer) bool { return w == term }
+	t.Setenv("NO_COLOR", "")
+
+	lg := New("TestColor")
+	lg.Color = true
+	lg.Warn, lg.Error = term, plain
+	lg.Warnf("Test message")
+	lg.Errorf("Test message")
+
+	m := regexp.MustCompile(`^\x1b\[33mW\x1b\[0m.*color_test.go:\d+: Test message
+$`)
+	if s := term.String(); !m.MatchString(s) {
+		t.Errorf("Got %q, want something matching %v from the terminal", s, m)
+	}
+	if s := plain.String(); !ematcher.MatchString(s) {
+		t.Errorf("Got %q, want something matching %v from error log", s, ematcher)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	term.Reset()
+	lg.Warnf("Test message")
+	if s := term.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %q, want something matching %v from the terminal with NO_COLOR", s, wmatcher)
+	}
+}
@@ -0,0 +1,36 @@
+package log
+
+import "runtime/debug"
+
+// SetCommit sets the code revision (e.g. a git commit hash) recorded in every
+// entry, so that structured records (see JSONFormatter) can be traced to the
+// code that wrote them.
+// Pass BuildCommit() to use the revision the binary was built from.
+// An empty commit leaves it out again.
+func (l *Logger) SetCommit(commit string) {
+	l.shared.commit.Store(commit)
+}
+
+// BuildCommit returns the VCS revision recorded in the binary's build info,
+// with a "-dirty" suffix if the working tree had local changes, or "" if there
+// is none (e.g. for a binary built outside a repository, or a test).
+func BuildCommit() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var rev string
+	var dirty bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if rev != "" && dirty {
+		rev += "-dirty"
+	}
+	return rev
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestSetCommit(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetCommit")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.SetCommit("4c1b4f3")
+	lg.WithFields(nil).Infof("Test message")
+	m := regexp.MustCompile(`^\{"level":"info",.*"msg":"Test message","commit":"4c1b4f3"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Reset()
+	lg.SetCommit("")
+	lg.Infof("Test message")
+	m = regexp.MustCompile(`^\{"level":"info",.*"msg":"Test message"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
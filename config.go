@@ -0,0 +1,100 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+)
+
+// Config is a snapshot of a Logger's effective settings, for diagnostics.
+type Config struct {
+	Name string
+
+	// Verbosity is the effective verbosity, including any BoostVerbosity.
+	Verbosity int
+
+	// Flags are the standard log package flags used to build message headers.
+	Flags int
+
+	// FlushEvery is the record count set by SetFlushEvery, or zero if off.
+	FlushEvery int
+
+	// Format is how records are rendered: "text" (the default), "json" for
+	// JSONFormatter, or the type of another Formatter (see SetFormatter).
+	Format string
+
+	// MinLevel is the level set by SetMinLevel.
+	MinLevel Level
+
+	// Best-effort descriptions of each writer, like a file name, "stderr", or
+	// "buffer".
+	Debug, Info, Warn, Error, Fatal, AuditTrail string
+}
+
+// Config returns a snapshot of the logger's current settings.
+func (l *Logger) Config() Config {
+	o := l.own()
+	l.shared.writersMu.RLock()
+	c := Config{
+		Name:       l.name,
+		Verbosity:  l.verbosity(),
+		FlushEvery: int(atomic.LoadInt64(&l.shared.flushEvery)),
+		Format:     "text",
+		MinLevel:   Level(atomic.LoadInt32(&l.shared.minLevel)),
+		Debug:      describeWriter(o.Debug),
+		Info:       describeWriter(o.Info),
+		Warn:       describeWriter(o.Warn),
+		Error:      describeWriter(o.Error),
+		Fatal:      describeWriter(o.Fatal),
+		AuditTrail: describeWriter(o.AuditTrail),
+	}
+	l.shared.writersMu.RUnlock()
+	if b := int(atomic.LoadInt64(&l.shared.boost.level)) - 1; b > c.Verbosity {
+		c.Verbosity = b
+	}
+	if lg, ok := l.i.(*log.Logger); ok {
+		c.Flags = lg.Flags()
+	}
+	if h, _ := l.shared.formatter.Load().(formatterHolder); h.Formatter != nil {
+		if _, ok := h.Formatter.(JSONFormatter); ok {
+			c.Format = "json"
+		} else {
+			c.Format = fmt.Sprintf("%T", h.Formatter)
+		}
+	}
+	return c
+}
+
+// Returns a short, human-readable description of where w writes.
+func describeWriter(w io.Writer) string {
+	switch w := w.(type) {
+	case nil:
+		return "none"
+	case *os.File:
+		switch w {
+		case os.Stdout:
+			return "stdout"
+		case os.Stderr:
+			return "stderr"
+		}
+		return w.Name()
+	case *ReopenWriter:
+		return w.path
+	case *GzipWriter:
+		if w.path != "" {
+			return w.path
+		}
+		return "gzip"
+	case *WSHub:
+		return "websocket"
+	case *bytes.Buffer:
+		return "buffer"
+	}
+	if w == io.Discard {
+		return "discard"
+	}
+	return fmt.Sprintf("%T", w)
+}
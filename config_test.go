@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	rw, err := NewReopenWriter(path)
+	if err != nil {
+		t.Fatalf("NewReopenWriter(%v) failed: %v", path, err)
+	}
+	defer rw.Close()
+
+	lg := New("TestConfig")
+	lg.SetVerbosity(3)
+	lg.SetFlushEvery(10)
+	lg.Info = new(bytes.Buffer)
+	lg.Warn = os.Stdout
+	lg.Error = rw
+	lg.AuditTrail = io.Discard
+
+	want := Config{
+		Name:       "TestConfig",
+		Verbosity:  3,
+		Flags:      log.Ldate | log.Ltime | log.Lshortfile,
+		FlushEvery: 10,
+		Format:     "text",
+		MinLevel:   LevelDebug,
+		Debug:      "discard",
+		Info:       "buffer",
+		Warn:       "stdout",
+		Error:      path,
+		Fatal:      "stderr",
+		AuditTrail: "discard",
+	}
+	if got := lg.Config(); got != want {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigFormat(t *testing.T) {
+	lg := New("TestConfigFormat")
+	lg.SetMinLevel(LevelWarn)
+	lg.SetFormatter(JSONFormatter{})
+	if got := lg.Config(); got.Format != "json" || got.MinLevel != LevelWarn {
+		t.Errorf("Got format %v and min level %v, want json and warn", got.Format, got.MinLevel)
+	}
+
+	lg.SetFormatter(NewCEFFormatter())
+	if got := lg.Config().Format; got != "*log.CEFFormatter" {
+		t.Errorf("Got format %v, want *log.CEFFormatter", got)
+	}
+}
@@ -0,0 +1,28 @@
+package log
+
+import "context"
+
+// The context key type for NewContext.
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying l, for FromContext to retrieve.
+// This carries a request's logger (e.g. one from WithFields) through handler
+// chains.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or Root if there is none (or
+// ctx is nil).
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return Root
+	}
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+		return l
+	}
+	return Root
+}
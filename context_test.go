@@ -0,0 +1,23 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	lg := New("TestFromContext").WithFields(Fields{"request_id": 7})
+	ctx := NewContext(context.Background(), lg)
+	if got := FromContext(ctx); got != lg {
+		t.Errorf("Got %v, want the logger from NewContext", got.Name())
+	}
+	if got := FromContext(WithForceSample(ctx)); got != lg {
+		t.Errorf("Got %v, want the logger from a parent context", got.Name())
+	}
+	if got := FromContext(context.Background()); got != Root {
+		t.Errorf("Got %v, want Root from a context without a logger", got.Name())
+	}
+	if got := FromContext(nil); got != Root {
+		t.Errorf("Got %v, want Root from a nil context", got.Name())
+	}
+}
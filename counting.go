@@ -0,0 +1,31 @@
+package log
+
+import "io"
+
+// discardLogable drops everything written to it.
+type discardLogable struct{}
+
+func (discardLogable) Output(calldepth int, s string) error { return nil }
+
+// NewCounting returns a Logger that renders and writes nothing, but still counts
+// the records logged at each level, along with a function that returns those
+// counts.
+// It is meant for tests and benchmarks that assert how much was logged.
+// Fatalf does not exit, since Exit is nil.
+func NewCounting() (*Logger, func() map[Level]int) {
+	l := &Logger{
+		calldepth:  3,
+		shared:     new(shared),
+		Verbosity:  Verbosity,
+		Debug:      io.Discard,
+		Info:       io.Discard,
+		Warn:       io.Discard,
+		Error:      io.Discard,
+		Fatal:      io.Discard,
+		AuditTrail: io.Discard,
+		lap:        newLapTimer(),
+	}
+	d := discardLogable{}
+	l.d, l.i, l.w, l.e, l.f, l.a, l.ic = d, d, d, d, d, d, d
+	return l, l.Counts
+}
@@ -0,0 +1,23 @@
+package log
+
+import "testing"
+
+func TestNewCounting(t *testing.T) {
+	lg, counts := NewCounting()
+	lg.SetVerbosity(1)
+
+	lg.Infof("One")
+	lg.V(1, "Two")
+	lg.V(2, "Not logged")
+	lg.Warnf("Three")
+	lg.Errorf("Four")
+	lg.Errorf("Five")
+	lg.Fatalf("Six")
+
+	got := counts()
+	for lv, want := range map[Level]int{LevelInfo: 2, LevelWarn: 1, LevelError: 2, LevelFatal: 1} {
+		if got[lv] != want {
+			t.Errorf("Got %v %v records, want %v", got[lv], lv, want)
+		}
+	}
+}
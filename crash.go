@@ -0,0 +1,68 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// SetCrashFile makes Fatalf write a crash report to path before calling Exit.
+// The report holds the fatal message, any records kept by CaptureRecent, and
+// the stacks of all goroutines.
+// It is written to a temporary file and renamed into place, so the last words
+// survive even when the normal writers are buffered or slow, and a reader never
+// sees half a report.
+// An empty path turns it off, which is the default.
+func (l *Logger) SetCrashFile(path string) {
+	l.shared.crashFile.Store(path)
+}
+
+// Writes the crash report for msg, if SetCrashFile is on.
+// Failures are reported to the base logger.
+func (l *Logger) writeCrashFile(msg string) {
+	path, _ := l.shared.crashFile.Load().(string)
+	if path == "" {
+		return
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s FATAL: %s\n", time.Now().Format(time.RFC3339Nano), msg)
+	if recs := l.Recent(-1); len(recs) > 0 {
+		b.WriteString("\nRecent records:\n")
+		for _, r := range recs {
+			fmt.Fprintf(&b, "%s %s: %s\n", r.Time.Format(time.RFC3339Nano), r.Level, r.Message)
+		}
+	}
+	buf := make([]byte, 1<<20)
+	b.WriteString("\nGoroutines:\n")
+	b.Write(buf[:runtime.Stack(buf, true)])
+
+	tmp := path + ".tmp"
+	if err := writeFileSynced(tmp, b.Bytes()); err != nil {
+		l.diagf("Failed to write %s crash file: %v", l.name, err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		l.diagf("Failed to write %s crash file: %v", l.name, err)
+	}
+}
+
+// Writes data to the file at path, like os.WriteFile, but syncs it to stable
+// storage before closing, so that a crash right after renaming it cannot leave
+// it empty or truncated.
+func writeFileSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if serr := f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSetCrashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crash.txt")
+	lg := New("TestSetCrashFile")
+	lg.Info = new(bytes.Buffer)
+	lg.Fatal = new(bytes.Buffer)
+	var exited bool
+	lg.Exit = func() {
+		exited = true
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Crash file not written before Exit: %v", err)
+		}
+	}
+
+	lg.SetCrashFile(path)
+	lg.CaptureRecent(10)
+	lg.Infof("Some context")
+	lg.Fatalf("Test %s", "message")
+
+	if !exited {
+		t.Errorf("The Exit function was not called")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read crash file: %v", err)
+	}
+	m := regexp.MustCompile(`(?s)^\S+ FATAL: \[ref=[0-9a-f]{8}\] Test message
+.*info: Some context
+.*Goroutines:
+.*TestSetCrashFile`)
+	if !m.Match(b) {
+		t.Errorf("Got %s, want something matching %v from the crash file", b, m)
+	}
+}
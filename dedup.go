@@ -0,0 +1,156 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// dedupState collapses runs of repeated messages into the first of them and a
+// count.
+type dedupState struct {
+	mu sync.Mutex
+
+	// How long a run may last, and how to tell whether messages repeat.
+	// A nil normalize means deduplication is off.
+	window    time.Duration
+	normalize func(string) string
+
+	// The current run.
+	key   string
+	level Level
+	l     *Logger // The logger that wrote the run's first message.
+	lg    Logable
+	since time.Time
+	count int         // Messages suppressed so far.
+	timer *time.Timer // Ends the run once its window passes, if count > 0.
+}
+
+// Returns whether msg (from l) repeats the current run, and so should not be
+// written.
+// Audit events are never suppressed, and do not end a run.
+// If msg ends a run with suppressed messages, first writes a summary of them
+// like the run's first message (depth is as for Logger.output, called from
+// this function).
+func (d *dedupState) suppress(l *Logger, level Level, lg Logable, depth int, msg string) bool {
+	if level == levelAudit {
+		return false
+	}
+	d.mu.Lock()
+	if d.normalize == nil {
+		d.mu.Unlock()
+		return false
+	}
+	key := d.normalize(msg)
+	now := time.Now()
+	if key == d.key && level == d.level && now.Sub(d.since) < d.window {
+		if d.count++; d.count == 1 {
+			since := d.since
+			d.timer = time.AfterFunc(since.Add(d.window).Sub(now), func() { d.expire(since) })
+		}
+		d.mu.Unlock()
+		return true
+	}
+	prevL, prevLevel, prevLg, n := d.l, d.level, d.lg, d.count
+	d.stop()
+	d.key, d.level, d.l, d.lg, d.since, d.count = key, level, l, lg, now, 0
+	d.mu.Unlock()
+
+	if n > 0 {
+		prevL.writeRepeated(prevLevel, prevLg, depth+1, n)
+	}
+	return false
+}
+
+// Ends the run that started at since, if it is still current, writing the
+// summary of its suppressed messages.
+// The summary is attributed to this function, as there is no caller.
+func (d *dedupState) expire(since time.Time) {
+	d.mu.Lock()
+	if !d.since.Equal(since) || d.count == 0 {
+		d.mu.Unlock()
+		return
+	}
+	l, level, lg, n := d.l, d.level, d.lg, d.count
+	d.key, d.l, d.lg, d.count, d.timer = "", nil, nil, 0, nil
+	d.mu.Unlock()
+
+	l.writeRepeated(level, lg, 2, n)
+}
+
+// Writes the summary of n suppressed messages, through the Formatter, hooks,
+// and so on like any other record, but bypassing deduplication itself.
+// depth is as for output, called from this function.
+func (l *Logger) writeRepeated(level Level, lg Logable, depth, n int) {
+	text, e := l.entry(level, nil, fmt.Sprintf("... (last message repeated %d times)", n))
+	l.emit(level, lg, depth+1, text, e)
+}
+
+// Stops the timer of the current run. d.mu must be held.
+func (d *dedupState) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+func (d *dedupState) set(window time.Duration, normalize func(string) string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stop()
+	d.window, d.normalize = window, normalize
+	d.key, d.l, d.lg, d.count = "", nil, nil, 0
+}
+
+// Replaces each run of digits with a single '#'.
+func stripDigits(s string) string {
+	var b strings.Builder
+	digits := false
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			if !digits {
+				b.WriteByte('#')
+			}
+			digits = true
+			continue
+		}
+		digits = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SetFuzzyDedup collapses messages at the same level that are identical apart
+// from their numbers (so "retry 1", "retry 2", ... collapse), for up to window
+// after the first of them.
+// Only the first message is written; when a different message arrives, or the
+// window passes, a summary line with the number of suppressed repeats is
+// written.
+// Audit events are never collapsed, so that none are lost from the trail.
+// A window of zero or less turns deduplication off, which is the default.
+// It replaces any SetDedup.
+func (l *Logger) SetFuzzyDedup(window time.Duration) {
+	if window <= 0 {
+		l.shared.dedup.set(0, nil)
+		return
+	}
+	l.shared.dedup.set(window, stripDigits)
+}
+
+// SetDedup collapses identical messages at the same level, written back to
+// back, for up to window after the first of them, like SetFuzzyDedup but
+// without ignoring numbers.
+// Messages are compared without their headers, so differing timestamps do not
+// keep them apart.
+// Audit events are never collapsed, so that none are lost from the trail.
+// A window of zero or less turns deduplication off, which is the default.
+// It replaces any SetFuzzyDedup.
+func (l *Logger) SetDedup(window time.Duration) {
+	if window <= 0 {
+		l.shared.dedup.set(0, nil)
+		return
+	}
+	l.shared.dedup.set(window, func(s string) string { return s })
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetFuzzyDedup(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetFuzzyDedup")
+	lg.Info = il
+
+	lg.SetFuzzyDedup(time.Minute)
+	for i := 1; i <= 5; i++ {
+		lg.Infof("attempt %d", i)
+	}
+	lg.Infof("done")
+
+	m := regexp.MustCompile(`^I.*dedup_test.go:\d+: attempt 1
+I.*dedup_test.go:\d+: \.\.\. \(last message repeated 4 times\)
+I.*dedup_test.go:\d+: done
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Truncate(0)
+	lg.SetFuzzyDedup(0)
+	lg.Infof("attempt 1")
+	lg.Infof("attempt 2")
+	if s := il.String(); !regexp.MustCompile("attempt 1\n.*attempt 2\n$").MatchString(s) {
+		t.Errorf("Got %v, want both attempts with deduplication off", s)
+	}
+}
+
+func TestSetDedup(t *testing.T) {
+	il := new(lockedBuffer)
+	lg := New("TestSetDedup")
+	lg.Info = il
+
+	lg.SetDedup(50 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		lg.Infof("Test message")
+	}
+	lg.Infof("Test message 2")
+	lg.Infof("Test message 3")
+	lg.Infof("Test message 3")
+	time.Sleep(100 * time.Millisecond)
+	lg.Infof("Test message 3")
+
+	m := regexp.MustCompile(`^I.*dedup_test.go:\d+: Test message
+I.*dedup_test.go:\d+: \.\.\. \(last message repeated 2 times\)
+I.*dedup_test.go:\d+: Test message 2
+I.*dedup_test.go:\d+: Test message 3
+I.*dedup.go:\d+: \.\.\. \(last message repeated 1 times\)
+I.*dedup_test.go:\d+: Test message 3
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+func TestSetDedupFormatted(t *testing.T) {
+	il := new(lockedBuffer)
+	lg := New("TestSetDedupFormatted")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+	lg.SetDedup(time.Minute)
+
+	lg.Infof("Test message")
+	lg.Infof("Test message")
+	lg.Infof("Test message 2")
+
+	m := regexp.MustCompile(`^\{"level":"info",.*"msg":"Test message"\}
+\{"level":"info",.*"msg":"... \(last message repeated 1 times\)"\}
+\{"level":"info",.*"msg":"Test message 2"\}
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	el := new(lockedBuffer)
+	lg = New("TestSetDedupFormatted")
+	lg.Error = el
+	lg.Encode = EncodeBase64
+	lg.SetDedup(20 * time.Millisecond)
+	lg.Errorf("Test message")
+	lg.Errorf("Test message")
+	time.Sleep(50 * time.Millisecond)
+
+	lines := strings.SplitAfter(el.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Got %q, want two lines from error log", el.String())
+	}
+	b, err := DecodeLine([]byte(lines[1]))
+	if m := regexp.MustCompile(`^E.*: \.\.\. \(last message repeated 1 times\)$`); err != nil || !m.MatchString(string(b)) {
+		t.Errorf("Got %q (error %v) once decoded, want something matching %v", b, err, m)
+	}
+}
+
+func TestSetDedupAudit(t *testing.T) {
+	al := new(bytes.Buffer)
+	lg := New("TestSetDedupAudit")
+	lg.AuditTrail = al
+
+	lg.SetDedup(time.Minute)
+	for i := 0; i < 3; i++ {
+		lg.Audit("login", Fields{"actor": "alice", "result": "ok"})
+	}
+
+	m := regexp.MustCompile(`^(A.*dedup_test.go:\d+: action=login actor=alice result=ok\n){3}$`)
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+}
+
+func TestSetFuzzyDedupAudit(t *testing.T) {
+	al := new(bytes.Buffer)
+	lg := New("TestSetFuzzyDedupAudit")
+	lg.AuditTrail = al
+
+	lg.SetFuzzyDedup(time.Minute)
+	for i := 1; i <= 3; i++ {
+		lg.Audit("login", Fields{"actor": fmt.Sprintf("user%d", i), "result": "ok"})
+	}
+
+	m := regexp.MustCompile(`^A.*dedup_test.go:\d+: action=login actor=user1 result=ok
+A.*dedup_test.go:\d+: action=login actor=user2 result=ok
+A.*dedup_test.go:\d+: action=login actor=user3 result=ok
+$`)
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+}
@@ -0,0 +1,30 @@
+package log
+
+import "sync/atomic"
+
+// SetDevMode turns DevInfof messages on or off.
+// They are off by default, so dev-only diagnostics never reach production logs.
+func (l *Logger) SetDevMode(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&l.shared.dev, v)
+}
+
+// DevInfof writes log messages at INFO level, but only in dev mode (see
+// SetDevMode).
+// Otherwise it is a cheap no-op; the message is not even formatted.
+func (l *Logger) DevInfof(format string, v ...interface{}) {
+	if atomic.LoadInt32(&l.shared.dev) != 0 {
+		l.write(LevelInfo, l.i, l.calldepth, format, v...)
+	}
+}
+
+// DevInfof writes log messages at INFO level to the root logger, but only in dev
+// mode.
+func DevInfof(format string, v ...interface{}) {
+	if atomic.LoadInt32(&Root.shared.dev) != 0 {
+		Root.write(LevelInfo, Root.i, Root.calldepth, format, v...)
+	}
+}
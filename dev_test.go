@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDevInfof(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestDevInfof")
+	lg.Info = il
+
+	lg.DevInfof("Not in dev mode")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log outside dev mode", s)
+	}
+
+	lg.SetDevMode(true)
+	lg.DevInfof("Test %s", "message")
+	if s := il.String(); !imatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log in dev mode", s, imatcher)
+	}
+
+	il.Truncate(0)
+	lg.SetDevMode(false)
+	lg.DevInfof("Not in dev mode")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log after leaving dev mode", s)
+	}
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// Reports a problem of the logger itself to its Diagnostics writer.
+func (l *Logger) diagf(format string, v ...interface{}) {
+	if l.Diagnostics == nil {
+		log.Printf(format, v...)
+		return
+	}
+	log.New(l.Diagnostics, "", log.LstdFlags).Printf(format, v...)
+}
+
+// ConfigureStdout points all of l's writers (but Debug, which is left alone)
+// at stdout, and its Diagnostics at stderr, as twelve-factor apps expect: the
+// application's logs on stdout, separate from the logger's own problems.
+func ConfigureStdout(l *Logger) {
+	configureStreams(l, os.Stdout, os.Stderr)
+}
+
+// Points l's writers at out, and its Diagnostics at diag.
+func configureStreams(l *Logger, out, diag io.Writer) {
+	l.SetInfo(out)
+	l.SetWarn(out)
+	l.SetError(out)
+	l.SetFatal(out)
+	l.SetAuditTrail(out)
+	l.Diagnostics = diag
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestConfigureStdout(t *testing.T) {
+	out, diag := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestConfigureStdout")
+	configureStreams(lg, out, diag)
+	lg.Infof("Test message")
+	lg.Errorf("Test message")
+
+	lg.SetWarn(failingWriter{})
+	lg.Warnf("Lost message")
+
+	m := regexp.MustCompile(`^I.*diag_test.go:\d+: Test message
+E.*diag_test.go:\d+: Test message
+$`)
+	if s := out.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from stdout", s, m)
+	}
+	m = regexp.MustCompile(`^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d Failed to write to TestConfigureStdout warn logger: disk full\.
+  Message: Lost message
+$`)
+	if s := diag.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from stderr", s, m)
+	}
+}
+
+func TestOnWriteError(t *testing.T) {
+	diag := new(bytes.Buffer)
+	lg := New("TestOnWriteError")
+	lg.Diagnostics = diag
+	lg.Warn = failingWriter{}
+
+	var levels []Level
+	var errs []error
+	lg.OnWriteError = func(level Level, err error) {
+		levels = append(levels, level)
+		errs = append(errs, err)
+	}
+	lg.Warnf("Lost message")
+
+	if len(errs) != 1 || levels[0] != LevelWarn || errs[0].Error() != "disk full" {
+		t.Errorf("Got errors %v at levels %v, want one disk full error at %v", errs, levels, LevelWarn)
+	}
+	if diag.Len() != 0 {
+		t.Errorf("Got %v from diagnostics, want nothing with OnWriteError set", diag)
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InfofDiff writes msg at INFO level, followed by a `changed.<key>=old→new`
+// field for each field (of structs) or key (of maps) that differs between old
+// and new.
+// Unchanged values are left out, which makes for far more readable config or
+// state change logs than dumping both values.
+// Values of any other kind, or of differing types, are compared as a whole,
+// under the key "value".
+func (l *Logger) InfofDiff(msg string, old, new interface{}) {
+	l.writeFields(LevelInfo, l.i, l.calldepth, diff(old, new), "%s", msg)
+}
+
+// InfofDiff writes msg at INFO level to the root logger, followed by the fields
+// that differ between old and new.
+// See Logger.InfofDiff for details.
+func InfofDiff(msg string, old, new interface{}) {
+	Root.writeFields(LevelInfo, Root.i, Root.calldepth, diff(old, new), "%s", msg)
+}
+
+// The stand-in for a map key that is only on one side of a diff.
+const absent = "<absent>"
+
+// Returns the changes from old to new, as changed.<key> fields.
+func diff(old, new interface{}) Fields {
+	f := make(Fields)
+	change := func(key string, o, n interface{}) {
+		f["changed."+key] = fmt.Sprintf("%s→%s", formatValue(o), formatValue(n))
+	}
+
+	ov, nv := deref(reflect.ValueOf(old)), deref(reflect.ValueOf(new))
+	if !ov.IsValid() || !nv.IsValid() || ov.Type() != nv.Type() {
+		if !reflect.DeepEqual(old, new) {
+			change("value", old, new)
+		}
+		return f
+	}
+
+	switch ov.Kind() {
+	case reflect.Struct:
+		t := ov.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+			if !reflect.DeepEqual(o, n) {
+				change(t.Field(i).Name, o, n)
+			}
+		}
+	case reflect.Map:
+		for _, k := range ov.MapKeys() {
+			o, n := ov.MapIndex(k), nv.MapIndex(k)
+			if !n.IsValid() {
+				change(fmt.Sprint(k.Interface()), o.Interface(), absent)
+			} else if !reflect.DeepEqual(o.Interface(), n.Interface()) {
+				change(fmt.Sprint(k.Interface()), o.Interface(), n.Interface())
+			}
+		}
+		for _, k := range nv.MapKeys() {
+			if !ov.MapIndex(k).IsValid() {
+				change(fmt.Sprint(k.Interface()), absent, nv.MapIndex(k).Interface())
+			}
+		}
+	default:
+		if !reflect.DeepEqual(old, new) {
+			change("value", old, new)
+		}
+	}
+	return f
+}
+
+// Follows v through any non-nil pointers.
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestInfofDiff(t *testing.T) {
+	type config struct {
+		Host  string
+		Port  int
+		Tags  []string
+		token string
+	}
+	il := new(bytes.Buffer)
+	lg := New("TestInfofDiff")
+	lg.Info = il
+
+	old := config{Host: "a", Port: 80, Tags: []string{"x"}, token: "s1"}
+	new := config{Host: "a", Port: 8080, Tags: []string{"x"}, token: "s2"}
+	lg.InfofDiff("Config changed", old, &new)
+	lg.InfofDiff("Limits changed", map[string]int{"cpu": 1, "mem": 2}, map[string]int{"cpu": 1, "disk": 3})
+
+	m := regexp.MustCompile(`^I.*diff_test.go:\d+: Config changed changed.Port=80→8080
+I.*diff_test.go:\d+: Limits changed changed.disk=<absent>→3 changed.mem=2→<absent>
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,23 @@
+package log
+
+import "encoding/base64"
+
+// EncodeBase64 encodes a record as standard base64, for Encode, so that it
+// survives transports that are not binary-safe.
+// DecodeLine reverses it.
+func EncodeBase64(b []byte) []byte {
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(out, b)
+	return out
+}
+
+// DecodeLine returns the record encoded by EncodeBase64 in line, which may end
+// with a newline.
+func DecodeLine(line []byte) ([]byte, error) {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		line = line[:n-1]
+	}
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(out, line)
+	return out[:n], err
+}
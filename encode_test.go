@@ -0,0 +1,39 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestEncode")
+	lg.Info = il
+	lg.Encode = EncodeBase64
+
+	lg.Infof("Test message")
+	lg.Infof("Multi\nline\x00message")
+
+	lines := strings.SplitAfter(il.String(), "\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("Got %q, want two lines from info log", il.String())
+	}
+	for i, m := range []*regexp.Regexp{
+		imatcher,
+		regexp.MustCompile("^I.*encode_test.go:\\d+: Multi\nline\x00message\n$"),
+	} {
+		if strings.ContainsAny(strings.TrimSuffix(lines[i], "\n"), "\n\x00") {
+			t.Errorf("Got %q, want an encoded line without newlines or NULs", lines[i])
+		}
+		b, err := DecodeLine([]byte(lines[i]))
+		if err != nil {
+			t.Errorf("DecodeLine(%q) failed: %v", lines[i], err)
+			continue
+		}
+		if s := string(b) + "\n"; !m.MatchString(s) {
+			t.Errorf("Got %q, want something matching %v once decoded", s, m)
+		}
+	}
+}
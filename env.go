@@ -0,0 +1,27 @@
+package log
+
+import "strconv"
+
+// VerbosityEnv is the environment variable that sets the default of the
+// verbosity flag, for deployments (like containers) where setting flags is
+// awkward.
+// The flag still wins when it is given.
+const VerbosityEnv = "LOG_VERBOSITY"
+
+// Applies VerbosityEnv, as found by lookup, to Verbosity.
+// Since this happens before flags are parsed, an explicit --verbosity replaces
+// it.
+// An invalid value leaves the verbosity at 0, with a warning.
+func verbosityFromEnv(lookup func(key string) (string, bool)) {
+	s, ok := lookup(VerbosityEnv)
+	if !ok || s == "" {
+		return
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		*Verbosity = 0
+		Root.Warnf("Ignoring invalid %s=%q: want an integer", VerbosityEnv, s)
+		return
+	}
+	*Verbosity = v
+}
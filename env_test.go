@@ -0,0 +1,47 @@
+package log
+
+import (
+	"bytes"
+	"flag"
+	"regexp"
+	"testing"
+)
+
+func TestVerbosityFromEnv(t *testing.T) {
+	defer func(v int) { *Verbosity = v }(*Verbosity)
+	wl := new(bytes.Buffer)
+	Root.Warn = wl
+	env := func(v string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			if key != VerbosityEnv {
+				return "", false
+			}
+			return v, true
+		}
+	}
+
+	*Verbosity = 0
+	verbosityFromEnv(env("3"))
+	if *Verbosity != 3 {
+		t.Errorf("Got verbosity %v, want 3 from the environment", *Verbosity)
+	}
+
+	// An explicit flag, parsed later, wins.
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(Verbosity, "verbosity", *Verbosity, "")
+	if err := fs.Parse([]string{"--verbosity=5"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if *Verbosity != 5 {
+		t.Errorf("Got verbosity %v, want 5 from the flag", *Verbosity)
+	}
+
+	verbosityFromEnv(env("loud"))
+	if *Verbosity != 0 {
+		t.Errorf("Got verbosity %v, want 0 after an invalid value", *Verbosity)
+	}
+	m := regexp.MustCompile(`^W.*Ignoring invalid LOG_VERBOSITY="loud": want an integer\n$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+}
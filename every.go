@@ -0,0 +1,99 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// everyState tracks the call sites of InfofEvery, WarnfEvery, and ErrorfEvery.
+type everyState struct {
+	mu    sync.Mutex
+	sites map[string]*everySite
+}
+
+type everySite struct {
+	last    time.Time // When the site last wrote.
+	dropped int       // Messages suppressed since.
+}
+
+// Returns whether the call site key may write now, given the window d, and if
+// so how many of its messages were suppressed since it last did.
+func (s *everyState) allow(key string, d time.Duration) (bool, int) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sites == nil {
+		s.sites = make(map[string]*everySite)
+	}
+	site := s.sites[key]
+	if site == nil {
+		s.sites[key] = &everySite{last: now}
+		return true, 0
+	}
+	if now.Sub(site.last) < d {
+		site.dropped++
+		return false, 0
+	}
+	dropped := site.dropped
+	site.last, site.dropped = now, 0
+	return true, dropped
+}
+
+// InfofEvery writes log messages at INFO level, like Infof, but at most once
+// per d from each call site, so a hot loop cannot flood the log.
+// The next message written from a site notes how many were suppressed, like
+// "(repeated 4213 times)".
+func (l *Logger) InfofEvery(d time.Duration, format string, v ...interface{}) {
+	every(l, LevelInfo, d, format, v...)
+}
+
+// InfofEvery writes log messages at INFO level to the root logger, but at most
+// once per d from each call site.
+// See Logger.InfofEvery for details.
+func InfofEvery(d time.Duration, format string, v ...interface{}) {
+	every(Root, LevelInfo, d, format, v...)
+}
+
+// WarnfEvery writes log messages at WARN level, but at most once per d from
+// each call site.
+// See Logger.InfofEvery for details.
+func (l *Logger) WarnfEvery(d time.Duration, format string, v ...interface{}) {
+	every(l, LevelWarn, d, format, v...)
+}
+
+// WarnfEvery writes log messages at WARN level to the root logger, but at most
+// once per d from each call site.
+// See Logger.InfofEvery for details.
+func WarnfEvery(d time.Duration, format string, v ...interface{}) {
+	every(Root, LevelWarn, d, format, v...)
+}
+
+// ErrorfEvery writes log messages at ERROR level, but at most once per d from
+// each call site.
+// See Logger.InfofEvery for details.
+func (l *Logger) ErrorfEvery(d time.Duration, format string, v ...interface{}) {
+	every(l, LevelError, d, format, v...)
+}
+
+// ErrorfEvery writes log messages at ERROR level to the root logger, but at
+// most once per d from each call site.
+// See Logger.InfofEvery for details.
+func ErrorfEvery(d time.Duration, format string, v ...interface{}) {
+	every(Root, LevelError, d, format, v...)
+}
+
+// Shared by all forms of ...Every, so the call depth is the same for each.
+func every(l *Logger, level Level, d time.Duration, format string, v ...interface{}) {
+	_, file, line, _ := runtime.Caller(2)
+	ok, dropped := l.shared.every.allow(fmt.Sprintf("%s:%d", file, line), d)
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf(format, v...)
+	if dropped > 0 {
+		msg += fmt.Sprintf(" (repeated %d times)", dropped)
+	}
+	l.write(level, l.logable(level), l.calldepth+1, "%s", msg)
+}
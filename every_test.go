@@ -0,0 +1,31 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestWarnfEvery(t *testing.T) {
+	wl := new(bytes.Buffer)
+	lg := New("TestWarnfEvery")
+	lg.Warn = wl
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 5; j++ {
+			lg.WarnfEvery(20*time.Millisecond, "Retrying %d", j)
+		}
+		lg.WarnfEvery(time.Hour, "Other site")
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	m := regexp.MustCompile(`^W.*every_test.go:\d+: Retrying 0
+W.*every_test.go:\d+: Other site
+W.*every_test.go:\d+: Retrying 0 \(repeated 4 times\)
+W.*every_test.go:\d+: Retrying 0 \(repeated 4 times\)
+$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+}
@@ -0,0 +1,18 @@
+package log
+
+// SetExitCode sets the status that the process exits with after a Fatal
+// message, for tooling that tells failures apart by status.
+// It is used by the default Exit function, and when Exit is nil and
+// FatalfRequiresExit is set; a replaced Exit ignores it.
+// The default is 1.
+func (l *Logger) SetExitCode(code int) {
+	l.shared.exitCode.Store(code)
+}
+
+// ExitCode returns the status from SetExitCode.
+func (l *Logger) ExitCode() int {
+	if code, ok := l.shared.exitCode.Load().(int); ok {
+		return code
+	}
+	return 1
+}
@@ -0,0 +1,26 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestSetExitCode(t *testing.T) {
+	defer func() { osExit = os.Exit }()
+	code := -1
+	osExit = func(c int) { code = c }
+
+	lg := New("TestSetExitCode")
+	lg.Fatal = new(bytes.Buffer)
+	lg.Fatalf("Test message")
+	if code != 1 {
+		t.Errorf("Got exit status %v, want the default of 1", code)
+	}
+
+	lg.SetExitCode(3)
+	lg.Fatalf("Test message")
+	if code != 3 {
+		t.Errorf("Got exit status %v, want 3 after SetExitCode(3)", code)
+	}
+}
@@ -0,0 +1,170 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Fields holds structured key-value data to attach to a log message.
+type Fields map[string]interface{}
+
+// String renders the fields as space-separated key=value pairs, sorted by key.
+func (f Fields) String() string {
+	return f.render(0)
+}
+
+// Like String, but renders at most max fields (the first by sorted key), noting
+// how many were dropped.
+// A max of zero or less renders them all.
+func (f Fields) render(max int) string {
+	keys, dropped := f.keys(max)
+	return f.renderKeys(keys, dropped)
+}
+
+// Renders the fields with the given keys, noting that dropped more were left
+// out.
+func (f Fields) renderKeys(keys []string, dropped int) string {
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%s", k, formatValue(f[k]))
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, " …(+%d fields dropped)", dropped)
+	}
+	return b.String()
+}
+
+// Like renderKeys, but puts each field on its own indented line, with the
+// values aligned, for reading in test output:
+//
+//	request_id: 7
+//	user:       ann
+func (f Fields) renderAligned(keys []string, dropped int) string {
+	width := 0
+	for _, k := range keys {
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "    %-*s %s", width+1, k+":", formatValue(f[k]))
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, "\n    …(+%d fields dropped)", dropped)
+	}
+	return b.String()
+}
+
+// Returns at most max of the keys (all if max is zero or less), in sorted
+// order, and how many were left out.
+func (f Fields) keys(max int) ([]string, int) {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if max > 0 && len(keys) > max {
+		return keys[:max], len(keys) - max
+	}
+	return keys, 0
+}
+
+// Returns at most max of the fields (all if max is zero or less), the first
+// by sorted key, and how many were left out.
+// Returns f itself if none were.
+func (f Fields) capped(max int) (Fields, int) {
+	keys, dropped := f.keys(max)
+	if dropped == 0 {
+		return f, 0
+	}
+	c := make(Fields, len(keys))
+	for _, k := range keys {
+		c[k] = f[k]
+	}
+	return c, dropped
+}
+
+// Returns a copy of f, which may be nil.
+func (f Fields) copy() Fields {
+	c := make(Fields, len(f)+1)
+	for k, v := range f {
+		c[k] = v
+	}
+	return c
+}
+
+// WithFields returns a derived logger that appends f to every message, merged
+// with any fields this logger already appends (f wins on a shared key).
+// The derived logger shares this logger's writers, verbosity, and other state.
+func (l *Logger) WithFields(f Fields) *Logger {
+	c := l.derive()
+	c.context = l.context.copy()
+	for k, v := range f {
+		c.context[k] = v
+	}
+	return c
+}
+
+// Retry returns a derived logger that appends attempt and max fields to every
+// message, so the messages of each attempt of a retry loop can be told apart
+// and the final failure read in context.
+func (l *Logger) Retry(attempt, max int) *Logger {
+	return l.WithFields(Fields{"attempt": attempt, "max": max})
+}
+
+// Returns msg followed by the rendered fields, noting that dropped more were
+// left out (by MaxFields).
+// Loggers from NewTest put each field on its own line, for readable test
+// output; others append them as key=value pairs.
+func (l *Logger) appendFields(msg string, f Fields, dropped int) string {
+	keys, _ := f.keys(0)
+	switch {
+	case len(f) == 0 && dropped == 0:
+		return msg
+	case l.test:
+		if msg == "" {
+			return f.renderAligned(keys, dropped)
+		}
+		return msg + "\n" + f.renderAligned(keys, dropped)
+	case msg == "":
+		return f.renderKeys(keys, dropped)
+	}
+	return msg + " " + f.renderKeys(keys, dropped)
+}
+
+// Renders a field value as text.
+// Nil (typed or not) becomes "<nil>", and pointers are followed to log the
+// value they point at rather than an address, unless the pointer knows how to
+// print itself.
+func formatValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	seen := make(map[uintptr]bool)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		switch rv.Interface().(type) {
+		case fmt.Stringer, error:
+			return fmt.Sprintf("%v", rv.Interface())
+		}
+		if seen[rv.Pointer()] {
+			// A pointer cycle; stop at the address rather than loop forever.
+			return fmt.Sprintf("%v", rv.Interface())
+		}
+		seen[rv.Pointer()] = true
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", rv.Interface())
+}
@@ -0,0 +1,125 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+type celsius float64
+
+func (c celsius) String() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+type userID struct{ id int }
+
+func (u *userID) String() string { return fmt.Sprintf("user-%d", u.id) }
+
+func TestFieldsString(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	var np *point
+	type cycle *cycle
+	var c cycle
+	c = &c
+
+	for _, tc := range []struct {
+		f    Fields
+		want string
+	}{
+		{Fields{}, ""},
+		{Fields{"b": 2, "a": "x"}, "a=x b=2"},
+		{Fields{"p": nil}, "p=<nil>"},
+		{Fields{"p": np}, "p=<nil>"},
+		{Fields{"p": &point{1, 2}}, "p={1 2}"},
+		{Fields{"b": bytes.NewBufferString("buf")}, "b=buf"},
+		{Fields{"temp": celsius(21.5)}, "temp=21.5°C"},
+		{Fields{"temp": new(celsius)}, "temp=0.0°C"},
+		{Fields{"user": &userID{7}}, "user=user-7"},
+	} {
+		if got := tc.f.String(); got != tc.want {
+			t.Errorf("Got %q, want %q from %#v", got, tc.want, tc.f)
+		}
+	}
+
+	// Only checks that a pointer cycle terminates.
+	_ = Fields{"c": c}.String()
+}
+
+func TestFieldsRender(t *testing.T) {
+	f := Fields{"d": 4, "c": 3, "b": 2, "a": 1}
+	if got, want := f.render(2), "a=1 b=2 …(+2 fields dropped)"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+	if got, want := f.render(4), "a=1 b=2 c=3 d=4"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestWithFields")
+	lg.Info = il
+
+	req := lg.WithFields(Fields{"user": "ann", "request_id": 7})
+	req.WithFields(Fields{"user": "bob", "step": 2}).Infof("Test %s", "message")
+	req.Infof("Test message")
+	lg.Infof("Test message")
+
+	m := regexp.MustCompile(`^I.*fields_test.go:\d+: Test message request_id=7 step=2 user=bob
+I.*fields_test.go:\d+: Test message request_id=7 user=ann
+I.*fields_test.go:\d+: Test message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	il, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestRetry")
+	lg.Info, lg.Error = il, el
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		rl := lg.Retry(attempt, 2)
+		rl.Infof("Connecting")
+		if attempt == 2 {
+			rl.Errorf("Giving up")
+		}
+	}
+
+	m := regexp.MustCompile(`^I.*fields_test.go:\d+: Connecting attempt=1 max=2
+I.*fields_test.go:\d+: Connecting attempt=2 max=2
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	m = regexp.MustCompile(`^E.*fields_test.go:\d+: Giving up attempt=2 max=2
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
+
+func TestNewTestFields(t *testing.T) {
+	ft := fakeTest{
+		info:  new(bytes.Buffer),
+		err:   new(bytes.Buffer),
+		fatal: new(bytes.Buffer),
+	}
+	lg := NewTest(ft, "TestNewTestFields", false).WithFields(Fields{"user": "ann", "request_id": 7})
+	lg.Infof("Test message")
+	lg.Log("Plain")
+
+	m := regexp.MustCompile(`^I.*fields_test.go:\d+: Test message
+    request_id: 7
+    user:       ann
+I.*fields_test.go:\d+: Plain
+    request_id: 7
+    user:       ann
+$`)
+	if s := ft.info.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// TrySetFile opens (creating if necessary) the file at path for appending, and
+// only if that succeeds makes it the writer for the given level.
+// This surfaces an unwritable log path right away, rather than when the first
+// message needs logging.
+// A relative path is resolved against the logger's directory, if it has one
+// (see InDir).
+// The logger does not close the file, nor whatever writer it replaces.
+func (l *Logger) TrySetFile(level Level, path string) error {
+	w := l.writerFor(level)
+	if w == nil {
+		return fmt.Errorf("cannot set a file for unknown log level %d", level)
+	}
+	if l.dir != "" && !filepath.IsAbs(path) {
+		if err := os.MkdirAll(l.dir, 0755); err != nil {
+			return fmt.Errorf("cannot use %s for %s logs: %w", l.dir, level, err)
+		}
+		path = filepath.Join(l.dir, path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot use %s for %s logs: %w", path, level, err)
+	}
+	l.shared.writersMu.Lock()
+	*w = f
+	l.shared.writersMu.Unlock()
+	return nil
+}
+
+// InDir returns a derived logger whose relative TrySetFile paths are resolved
+// against dir (itself relative to this logger's directory, if any), which is
+// created on demand.
+// This keeps the logs of each component of an application in its own
+// subdirectory.
+//
+// Unlike other derived loggers, the result has writers of its own (starting as
+// this logger's current ones), so setting a file on it leaves this logger alone.
+func (l *Logger) InDir(dir string) *Logger {
+	c := l.derive()
+	if l.dir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(l.dir, dir)
+	}
+	c.dir = dir
+	if lg, ok := l.i.(*log.Logger); ok {
+		if _, ok := lg.Writer().(*rewriter); ok {
+			c.bind(lg.Flags())
+		}
+	}
+	return c
+}
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrySetFile(t *testing.T) {
+	dir := t.TempDir()
+	wl := new(bytes.Buffer)
+	lg := New("TestTrySetFile")
+	lg.Warn = wl
+
+	bad := filepath.Join(dir, "missing", "test.log")
+	if err := lg.TrySetFile(LevelWarn, bad); err == nil {
+		t.Errorf("Got nil error, want one for unwritable path %v", bad)
+	}
+	if lg.Warn != wl {
+		t.Errorf("Got %v, want the warn writer left alone after a failure", lg.Warn)
+	}
+
+	good := filepath.Join(dir, "test.log")
+	if err := lg.TrySetFile(LevelWarn, good); err != nil {
+		t.Fatalf("Got error %v, want nil for writable path %v", err, good)
+	}
+	defer lg.Warn.(*os.File).Close()
+	lg.Warnf("Test %s", "message")
+	if b, err := os.ReadFile(good); err != nil || !wmatcher.Match(b) {
+		t.Errorf("Got %q (err %v), want something matching %v from %v", b, err, wmatcher, good)
+	}
+}
+
+func TestInDir(t *testing.T) {
+	dir := t.TempDir()
+	wl := new(bytes.Buffer)
+	lg := New("TestInDir")
+	lg.Warn = wl
+	db := lg.InDir(dir).InDir("db")
+
+	if err := db.TrySetFile(LevelWarn, "warn.log"); err != nil {
+		t.Fatalf("TrySetFile failed: %v", err)
+	}
+	defer db.Warn.(*os.File).Close()
+	db.Warnf("Test %s", "message")
+
+	path := filepath.Join(dir, "db", "warn.log")
+	if b, err := os.ReadFile(path); err != nil || !wmatcher.Match(b) {
+		t.Errorf("Got %q (err %v), want something matching %v from %v", b, err, wmatcher, path)
+	}
+	if s := wl.String(); len(s) > 0 {
+		t.Errorf("Got %v, want the parent's warn log untouched", s)
+	}
+}
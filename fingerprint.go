@@ -0,0 +1,109 @@
+package log
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Returns a stable fingerprint for a message, from its format string and the
+// file:line that logged it (skip frames above the caller of fingerprint).
+// The arguments are deliberately left out, so the "same" error groups together
+// whatever values it was logged with.
+func fingerprint(format string, skip int) string {
+	h := fnv.New64a()
+	h.Write([]byte(format))
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		fmt.Fprintf(h, "\x00%s:%d", file, line)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// ErrorfGrouped writes log messages at ERROR level, with a fingerprint field
+// that identifies the format string and call site.
+// Error grouping tools can use it to group the "same" error regardless of the
+// argument values.
+func (l *Logger) ErrorfGrouped(format string, v ...interface{}) {
+	errorfGrouped(l, format, v...)
+}
+
+// ErrorfGrouped writes log messages at ERROR level to the root logger, with a
+// fingerprint field.
+// See Logger.ErrorfGrouped for details.
+func ErrorfGrouped(format string, v ...interface{}) {
+	errorfGrouped(Root, format, v...)
+}
+
+// Shared by both forms of ErrorfGrouped, so the call depth is the same for each.
+func errorfGrouped(l *Logger, format string, v ...interface{}) {
+	fp := fingerprint(format, 2)
+	l.writeFields(LevelError, l.e, l.calldepth+1, Fields{"fingerprint": fp}, format, v...)
+}
+
+// cooldownState suppresses messages whose fingerprint was written recently.
+type cooldownState struct {
+	mu     sync.Mutex
+	d      time.Duration // Zero when off.
+	fps    map[string]*fingerprintRun
+	cutoff time.Time // When the map was last pruned.
+}
+
+type fingerprintRun struct {
+	last       time.Time // When the fingerprint was last written.
+	suppressed int       // Messages suppressed since then.
+}
+
+// Returns whether a message with the given fields may be written, and if so,
+// how many with the same fingerprint were suppressed before it.
+func (c *cooldownState) allow(f Fields) (bool, int) {
+	fp, ok := f["fingerprint"]
+	if !ok {
+		return true, 0
+	}
+	key := fmt.Sprint(fp)
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.d <= 0 {
+		return true, 0
+	}
+	if now.Sub(c.cutoff) >= c.d {
+		// Forget fingerprints that are past their cooldown and have
+		// nothing to report, so the map does not grow without bound.
+		for k, r := range c.fps {
+			if r.suppressed == 0 && now.Sub(r.last) >= c.d {
+				delete(c.fps, k)
+			}
+		}
+		c.cutoff = now
+	}
+	r := c.fps[key]
+	if r == nil {
+		c.fps[key] = &fingerprintRun{last: now}
+		return true, 0
+	}
+	if now.Sub(r.last) < c.d {
+		r.suppressed++
+		return false, 0
+	}
+	n := r.suppressed
+	r.last, r.suppressed = now, 0
+	return true, n
+}
+
+// SetFingerprintCooldown suppresses messages with the same fingerprint field
+// (like those from ErrorfGrouped, or with a "fingerprint" set through
+// WithFields) for d after one is written, whatever their level or call site.
+// This coalesces the same logical error reported from many places.
+// The next message written with the fingerprint has a suppressed field with
+// the number of messages suppressed since the last one, if any.
+// Audit events are never suppressed, and do not start a cooldown.
+// A d of zero or less turns the cooldown off, which is the default.
+func (l *Logger) SetFingerprintCooldown(d time.Duration) {
+	c := &l.shared.cooldown
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.d, c.fps = d, make(map[string]*fingerprintRun)
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestErrorfGrouped(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestErrorfGrouped")
+	lg.Error = el
+
+	m := regexp.MustCompile(`^E.*fingerprint_test.go:\d+: Test message \d fingerprint=([0-9a-f]{16})\n$`)
+	var fps []string
+	for i := 0; i < 2; i++ {
+		el.Truncate(0)
+		lg.ErrorfGrouped("Test message %d", i)
+		sm := m.FindStringSubmatch(el.String())
+		if sm == nil {
+			t.Fatalf("Got %v, want something matching %v from error log", el, m)
+		}
+		fps = append(fps, sm[1])
+	}
+	if fps[0] != fps[1] {
+		t.Errorf("Got fingerprints %v and %v, want them equal for the same format and call site", fps[0], fps[1])
+	}
+
+	el.Truncate(0)
+	lg.ErrorfGrouped("Test message %d", 0)
+	if sm := m.FindStringSubmatch(el.String()); sm == nil || sm[1] == fps[0] {
+		t.Errorf("Got %v, want a different fingerprint from a different call site", el)
+	}
+}
+
+func TestFingerprintCooldown(t *testing.T) {
+	wl, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestFingerprintCooldown")
+	lg.Warn, lg.Error = wl, el
+	lg.SetFingerprintCooldown(50 * time.Millisecond)
+
+	db := lg.WithFields(Fields{"fingerprint": "db-down"})
+	db.Errorf("Query failed")
+	db.Warnf("Retrying query")
+	db.Errorf("Query failed")
+	lg.Errorf("Unrelated")
+	time.Sleep(60 * time.Millisecond)
+	db.Warnf("Retrying query")
+
+	m := regexp.MustCompile(`^E.*fingerprint_test.go:\d+: Query failed fingerprint=db-down
+E.*fingerprint_test.go:\d+: Unrelated
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+	m = regexp.MustCompile(`^W.*fingerprint_test.go:\d+: Retrying query fingerprint=db-down suppressed=2
+$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+}
+
+func TestFingerprintCooldownAudit(t *testing.T) {
+	el, al := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestFingerprintCooldownAudit")
+	lg.Error, lg.AuditTrail = el, al
+	lg.SetFingerprintCooldown(time.Minute)
+
+	lg.WithFields(Fields{"fingerprint": "login"}).Errorf("Login failed")
+	lg.Audit("login", Fields{"actor": "alice", "result": "denied", "fingerprint": "login"})
+	lg.Audit("login", Fields{"actor": "alice", "result": "denied", "fingerprint": "login"})
+
+	m := regexp.MustCompile(`^(A.*fingerprint_test.go:\d+: action=login actor=alice fingerprint=login result=denied\n){2}$`)
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+}
@@ -0,0 +1,23 @@
+package log
+
+import "sync/atomic"
+
+// The level set by SetLevelFloor, or numLevels for none.
+var levelFloor int32 = int32(numLevels)
+
+// SetLevelFloor guarantees that messages at level or above are written by every
+// logger, whatever the code has configured to suppress them: MuteLevel,
+// SetMinLevel, and Limiter drops are ignored for those levels.
+// This is a policy knob for operators, e.g. to always keep warnings.
+// A level outside Debug through Fatal removes the floor, which is the default.
+func SetLevelFloor(level Level) {
+	if level < LevelDebug || level > LevelFatal {
+		level = numLevels
+	}
+	atomic.StoreInt32(&levelFloor, int32(level))
+}
+
+// Returns whether level is at or above the floor, so may not be suppressed.
+func aboveFloor(level Level) bool {
+	return int32(level) >= atomic.LoadInt32(&levelFloor)
+}
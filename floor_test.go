@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetLevelFloor(t *testing.T) {
+	il, wl := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetLevelFloor")
+	lg.Info = il
+	lg.Warn = wl
+	// Configure the logger to write only errors and above.
+	defer lg.MuteLevel(LevelInfo)()
+	defer lg.MuteLevel(LevelWarn)()
+
+	SetLevelFloor(LevelWarn)
+	defer SetLevelFloor(-1)
+	lg.Infof("Dropped")
+	lg.Warnf("Test message")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log below the floor", s)
+	}
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log at the floor", s, wmatcher)
+	}
+
+	lg.SetLimiter(fakeLimiter(false))
+	wl.Truncate(0)
+	lg.Warnf("Test message")
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from limited warn log at the floor", s, wmatcher)
+	}
+
+	SetLevelFloor(-1)
+	wl.Truncate(0)
+	lg.Warnf("Dropped")
+	if s := wl.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from muted warn log without a floor", s)
+	}
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"io"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// flusher is implemented by buffered writers, like bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// syncer is implemented by writers that can commit what was written to stable
+// storage, like os.File.
+type syncer interface {
+	Sync() error
+}
+
+// SetFlushEvery makes the logger flush its writers after every n records.
+// Only writers with a `Flush() error` method (e.g. bufio.Writer) are flushed;
+// others are left alone.
+// This gives a predictable durability cadence, regardless of message sizes.
+// An n of zero or less turns it off, which is the default.
+func (l *Logger) SetFlushEvery(n int) {
+	atomic.StoreInt64(&l.shared.flushEvery, int64(n))
+}
+
+// Flushes each distinct writer of the logger that can be flushed.
+// Errors are reported to the base logger.
+func (l *Logger) flush() {
+	for _, w := range l.writers() {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				l.diagf("Failed to flush %s logger: %v", l.name, err)
+			}
+		}
+	}
+}
+
+// Flushes the logger's writers as flush does, and then syncs each that can be
+// synced (like files), so that nothing buffered is lost before exiting.
+// Sync errors are ignored, since terminals and pipes cannot be synced.
+func (l *Logger) flushAll() {
+	l.flush()
+	for _, w := range l.writers() {
+		if s, ok := w.(syncer); ok {
+			s.Sync()
+		}
+	}
+}
+
+// Returns each distinct writer of the logger.
+func (l *Logger) writers() []io.Writer {
+	o := l.own()
+	l.shared.writersMu.RLock()
+	all := []io.Writer{o.Debug, o.Info, o.Warn, o.Error, o.Fatal, o.AuditTrail}
+	l.shared.writersMu.RUnlock()
+	var ws []io.Writer
+	for _, w := range all {
+		if w != nil && !containsWriter(ws, w) {
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// Returns whether w is in ws, as by sameWriter.
+func containsWriter(ws []io.Writer, w io.Writer) bool {
+	for _, o := range ws {
+		if sameWriter(o, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether a and b are the same writer.
+// Writers of types that cannot be compared (like func types, or structs holding
+// slices) are never the same as any, since comparing them would panic.
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// Flushes the logger's writers as flushAll does, but waits no longer than d for
+// it to finish.
+// Does nothing if d is zero or less.
+func (l *Logger) flushWithin(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		l.flushAll()
+		close(done)
+	}()
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+	}
+}
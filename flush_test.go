@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+)
+
+// countingFlusher is a writer that counts calls to Flush.
+type countingFlusher struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (c *countingFlusher) Flush() error {
+	c.flushes++
+	return nil
+}
+
+func TestSetFlushEvery(t *testing.T) {
+	cf := &countingFlusher{}
+	lg := New("TestSetFlushEvery")
+	lg.Info = cf
+	lg.Warn = cf
+
+	lg.Infof("Not flushed")
+	if cf.flushes != 0 {
+		t.Errorf("Got %v flushes, want 0 before SetFlushEvery", cf.flushes)
+	}
+
+	lg.SetFlushEvery(3)
+	for i := 1; i <= 7; i++ {
+		if i%2 == 0 {
+			lg.Warnf("Record %d", i)
+		} else {
+			lg.Infof("Record %d", i)
+		}
+		if want := i / 3; cf.flushes != want {
+			t.Errorf("Got %v flushes after %v records, want %v", cf.flushes, i, want)
+		}
+	}
+}
+
+// slowFlusher is a writer whose Flush takes a while, then closes flushed.
+type slowFlusher struct {
+	bytes.Buffer
+	delay   time.Duration
+	flushed chan struct{}
+}
+
+func (s *slowFlusher) Flush() error {
+	time.Sleep(s.delay)
+	close(s.flushed)
+	return nil
+}
+
+func TestFatalGracePeriod(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		delay, grace time.Duration
+		wantFlushed  bool
+	}{
+		{"flushed first", 10 * time.Millisecond, 5 * time.Second, true},
+		{"timed out first", 5 * time.Second, 50 * time.Millisecond, false},
+	} {
+		sf := &slowFlusher{delay: tc.delay, flushed: make(chan struct{})}
+		lg := New("TestFatalGracePeriod")
+		lg.Fatal = sf
+		lg.FatalGracePeriod = tc.grace
+		flushed := false
+		lg.Exit = func() {
+			select {
+			case <-sf.flushed:
+				flushed = true
+			default:
+			}
+		}
+
+		start := time.Now()
+		lg.Fatalf("Test message")
+		if took := time.Since(start); took >= time.Second {
+			t.Errorf("%s: Got Exit after %v, want it after the flusher or grace period, whichever is first", tc.name, took)
+		}
+		if flushed != tc.wantFlushed {
+			t.Errorf("%s: Got flushed %v before Exit, want %v", tc.name, flushed, tc.wantFlushed)
+		}
+	}
+}
+
+// syncingWriter is a writer that counts calls to Sync.
+type syncingWriter struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (s *syncingWriter) Sync() error {
+	s.syncs++
+	return nil
+}
+
+func TestFatalfFlushes(t *testing.T) {
+	fl, il := new(bytes.Buffer), new(syncingWriter)
+	bw := bufio.NewWriter(fl)
+	lg := New("TestFatalfFlushes")
+	lg.Fatal, lg.Info = bw, il
+	lg.Exit = func() {
+		if s := fl.String(); !fmatcher.MatchString(s) {
+			t.Errorf("Got %v at Exit, want something matching %v from the buffered fatal log", s, fmatcher)
+		}
+		if il.syncs != 1 {
+			t.Errorf("Got %v syncs of info log at Exit, want 1", il.syncs)
+		}
+	}
+
+	lg.Fatalf("Test message")
+}
+
+// writerFunc is a writer of a type that cannot be compared.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestFatalfUncomparableWriter(t *testing.T) {
+	fl := new(bytes.Buffer)
+	lg := New("TestFatalfUncomparableWriter")
+	lg.Fatal, lg.Info = writerFunc(fl.Write), writerFunc(fl.Write)
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.Fatalf("Test message")
+	if !exited {
+		t.Errorf("Got Exit not called, want it called with func-typed writers")
+	}
+	if s := fl.String(); !fmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, fmatcher)
+	}
+}
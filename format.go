@@ -0,0 +1,347 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single log record, as given to a Formatter.
+type Entry struct {
+	Level Level
+	Time  time.Time
+
+	// File and Line locate the caller that logged the entry, or the one named
+	// by InfofFrom (with no Line) or InfofAt_Loc.
+	// File is empty if the logger does not record callers.
+	File string
+	Line int
+
+	// Message is the formatted message, without the fields.
+	Message string
+
+	// Fields are those given with the message and by WithFields, up to the
+	// logger's MaxFields.
+	Fields Fields
+
+	// Dropped is how many more fields MaxFields left out.
+	Dropped int
+
+	// Stack is the stack from IncludeStack, if any, as indented lines.
+	Stack string
+
+	// Commit is the code revision from SetCommit, if any.
+	Commit string
+
+	// Schema is the version from SetSchemaVersion, if any.
+	Schema string
+}
+
+// Formatter renders log entries, for output other than the default text form
+// produced by the standard log package.
+type Formatter interface {
+	// Format returns the bytes to write for e, including any trailing newline.
+	Format(e Entry) []byte
+}
+
+// EntryWriter is implemented by writers that take whole entries rather than
+// rendered text, like SyslogWriter, KafkaWriter, and the one from
+// OTLPExporter.
+// Assigned directly to one of a logger's writers, it is given each Entry in
+// place of the rendered record; neither the Formatter nor Encode apply.
+type EntryWriter interface {
+	WriteEntry(e Entry) error
+}
+
+// Wraps a Formatter, so that an atomic.Value always holds the same type.
+type formatterHolder struct {
+	Formatter
+}
+
+// SetFormatter makes the logger render records with f, in place of the
+// standard log package's prefix and flags.
+// The records still go to the usual writers (Info, Warn, etc.), except that an
+// EntryWriter is given the entry itself.
+// A nil f restores the default text form.
+func (l *Logger) SetFormatter(f Formatter) {
+	l.shared.formatter.Store(formatterHolder{f})
+}
+
+// SetTimeKey renames the "time" member of records from JSONFormatter, e.g. to
+// "@timestamp" for Elasticsearch.
+// An empty key restores the default.
+func (l *Logger) SetTimeKey(key string) {
+	l.shared.timeKey.Store(key)
+}
+
+// SetLevelKey renames the "level" member of records from JSONFormatter, e.g.
+// to "severity".
+// An empty key restores the default.
+func (l *Logger) SetLevelKey(key string) {
+	l.shared.levelKey.Store(key)
+}
+
+// SetMessageKey renames the "msg" member of records from JSONFormatter, e.g.
+// to "message".
+// An empty key restores the default.
+func (l *Logger) SetMessageKey(key string) {
+	l.shared.msgKey.Store(key)
+}
+
+// Builds the record of a message: its text form, with the fields appended, and
+// its Entry (but for the time and caller) for a Formatter.
+// The message is prefixed with the logger's Sub name, fields are merged over
+// those from WithFields, and secrets masked if DetectSecrets is set; then all
+// but MaxFields of them are dropped (except from audit records).
+func (l *Logger) entry(level Level, fields Fields, msg string) (string, Entry) {
+	if l.sub != "" {
+		msg = "[" + l.sub + "] " + msg
+	}
+	f := fields
+	if len(l.context) > 0 {
+		f = l.context.copy()
+		for k, v := range fields {
+			f[k] = v
+		}
+	}
+	f = l.shared.pii.apply(f)
+	if l.DetectSecrets {
+		msg, f = maskSecrets(msg, f)
+	}
+	max := l.MaxFields
+	if level == levelAudit {
+		max = 0
+	}
+	f, dropped := f.capped(max)
+	e := Entry{Level: level, Message: msg, Fields: f, Dropped: dropped, Stack: strings.TrimPrefix(l.trace, "\n")}
+	if l.loc != "" {
+		e.File, e.Line = l.loc, l.locLine
+	}
+	return l.renderEntry(e), e
+}
+
+// Returns the text form of e: its message, then its fields, then its stack.
+// A caller named by InfofFrom or InfofAt_Loc goes before the message (after
+// any Sub name), as the standard log package has no place for it.
+func (l *Logger) renderEntry(e Entry) string {
+	s := l.appendFields(e.Message, e.Fields, e.Dropped)
+	if e.Stack != "" {
+		s += "\n" + e.Stack
+	}
+	if l.loc != "" {
+		loc := l.loc
+		if l.locLine != 0 {
+			loc += ":" + strconv.Itoa(l.locLine)
+		}
+		var sub string
+		if l.sub != "" {
+			sub = "[" + l.sub + "] "
+		}
+		s = sub + loc + ": " + strings.TrimPrefix(s, sub)
+	}
+	return s
+}
+
+// Writes a record to lg: e itself if the writer is an EntryWriter, or e
+// rendered by the logger's Formatter if it has one, otherwise text in the
+// standard log package's form (with the logger's time format).
+// depth is as for Logable.Output, as called by this function's caller.
+// If e has a time, it and its caller are kept (as for a record written late).
+//
+// For a *log.Logger, the record is rendered here, and only its prefix, flags,
+// and writer are used.
+func (l *Logger) output(lg Logable, depth int, text string, e Entry) error {
+	std, ok := lg.(*log.Logger)
+	if !ok {
+		return lg.Output(depth+1, text)
+	}
+	if e.Time.IsZero() {
+		stamp(std, depth+1, &e)
+	}
+	e.Commit, _ = l.shared.commit.Load().(string)
+	e.Schema, _ = l.shared.schema.Load().(string)
+	flags := std.Flags()
+	dst := std.Writer()
+	if rw, ok := dst.(*rewriter); ok {
+		dst = rw.dest()
+	}
+	if ew, ok := dst.(EntryWriter); ok {
+		return ew.WriteEntry(e)
+	}
+	var b []byte
+	if h, _ := l.shared.formatter.Load().(formatterHolder); h.Formatter != nil {
+		if jf, ok := h.Formatter.(JSONFormatter); ok {
+			jf.keys.time, _ = l.shared.timeKey.Load().(string)
+			jf.keys.level, _ = l.shared.levelKey.Load().(string)
+			jf.keys.msg, _ = l.shared.msgKey.Load().(string)
+			h.Formatter = jf
+		}
+		b = h.Format(e)
+	} else {
+		prefix := std.Prefix()
+		if l.Color {
+			prefix = colorPrefix(std, e.Level, prefix)
+		}
+		b = l.formatText(prefix, flags, e, text)
+	}
+	if l.Encode != nil {
+		b = append(l.Encode(bytes.TrimSuffix(b, []byte("\n"))), '\n')
+	}
+	l.shared.outMu.Lock()
+	defer l.shared.outMu.Unlock()
+	_, err := std.Writer().Write(b)
+	return err
+}
+
+// Returns e as text, without the time or level: the caller (if any), the
+// message, the fields, and the stack.
+func (e Entry) text() string {
+	keys, _ := e.Fields.keys(0)
+	s := e.Message
+	if len(keys) > 0 || e.Dropped > 0 {
+		s += " " + e.Fields.renderKeys(keys, e.Dropped)
+	}
+	switch {
+	case e.Line != 0:
+		s = e.File + ":" + strconv.Itoa(e.Line) + ": " + s
+	case e.File != "":
+		s = e.File + ": " + s
+	}
+	if e.Stack != "" {
+		s += "\n" + e.Stack
+	}
+	return s
+}
+
+// Sets the time of e to now, and its caller (if std's flags call for one, and
+// it has none) to where std.Output would find it.
+// depth is as for std.Output, as called by this function's caller.
+func stamp(std *log.Logger, depth int, e *Entry) {
+	e.Time = time.Now()
+	flags := std.Flags()
+	if e.File == "" && flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if _, file, line, ok := runtime.Caller(depth); ok {
+			if flags&log.Lshortfile != 0 {
+				file = filepath.Base(file)
+			}
+			e.File, e.Line = file, line
+		}
+	}
+}
+
+// JSONFormatter renders each entry as a line of JSON, like
+//
+//	{"level":"info","time":"2006-01-02T15:04:05.999999999Z","file":"main.go","line":42,"msg":"Hello","fields":{"user":"ann"}}
+//
+// A commit from SetCommit is included as "commit", and a version from
+// SetSchemaVersion as "schema".
+// The "time", "level", and "msg" keys may be renamed with SetTimeKey and
+// friends.
+// Field values are encoded as JSON where they can be, except that errors and
+// fmt.Stringers are encoded as their text.
+type JSONFormatter struct {
+	// Set from the logger's when it formats an entry.
+	keys jsonKeys
+}
+
+// The keys of JSONFormatter's reserved members, as set by SetTimeKey and
+// friends; empty for the defaults.
+type jsonKeys struct {
+	time, level, msg string
+}
+
+// Format returns e as a line of JSON.
+func (f JSONFormatter) Format(e Entry) []byte {
+	k := f.keys
+	if k.time == "" {
+		k.time = "time"
+	}
+	if k.level == "" {
+		k.level = "level"
+	}
+	if k.msg == "" {
+		k.msg = "msg"
+	}
+
+	b := []byte{'{'}
+	add := func(key string, v interface{}) {
+		if len(b) > 1 {
+			b = append(b, ',')
+		}
+		kb, _ := json.Marshal(key)
+		vb, err := json.Marshal(v)
+		if err != nil {
+			// Only possible for a time outside years 0-9999.
+			vb, _ = json.Marshal(time.Time{})
+		}
+		b = append(append(append(b, kb...), ':'), vb...)
+	}
+	add(k.level, e.Level)
+	add(k.time, e.Time)
+	if e.File != "" {
+		add("file", e.File)
+	}
+	if e.Line != 0 {
+		add("line", e.Line)
+	}
+	add(k.msg, e.Message)
+	if len(e.Fields) > 0 {
+		fields := make(map[string]interface{}, len(e.Fields))
+		for name, v := range e.Fields {
+			fields[name] = jsonValue(v)
+		}
+		add("fields", fields)
+	}
+	if e.Dropped > 0 {
+		// How many fields MaxFields left out.
+		add("fields_dropped", e.Dropped)
+	}
+	if e.Stack != "" {
+		add("stack", e.Stack)
+	}
+	if e.Commit != "" {
+		add("commit", e.Commit)
+	}
+	if e.Schema != "" {
+		add("schema", e.Schema)
+	}
+	return append(b, '}', '\n')
+}
+
+// Returns v in a form that encodes well as JSON.
+// Nil values (including nil pointers, as to an error or fmt.Stringer) are
+// encoded as null.
+func jsonValue(v interface{}) interface{} {
+	if isNil(v) {
+		return nil
+	}
+	switch v.(type) {
+	case json.Marshaler:
+		return v
+	case error, fmt.Stringer:
+		return formatValue(v)
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return formatValue(v)
+	}
+	return v
+}
+
+// Returns whether v is nil, or a nil pointer, map, slice, func, or channel.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
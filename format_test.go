@@ -0,0 +1,162 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	il, wl := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestJSONFormatter")
+	lg.Info = il
+	lg.Warn = wl
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.WithFields(Fields{"user": "ann", "n": 2}).Warnf("Test %s", "message")
+	lg.InfofFrom("caller", "Test message")
+
+	var got struct {
+		Level  string
+		Time   time.Time
+		File   string
+		Line   int
+		Msg    string
+		Fields map[string]interface{}
+	}
+	if err := json.Unmarshal(wl.Bytes(), &got); err != nil {
+		t.Fatalf("Got %v, want JSON from warn log: %v", wl.String(), err)
+	}
+	if got.Fields["user"] != "ann" || got.Fields["n"] != 2.0 {
+		t.Errorf("Got fields %v, want user and n", got.Fields)
+	}
+	if got.Level != "warn" || got.File != "format_test.go" || got.Line == 0 || got.Msg != "Test message" || time.Since(got.Time) > time.Minute {
+		t.Errorf("Got %+v, want a recent warn entry from format_test.go", got)
+	}
+	if !strings.HasSuffix(wl.String(), "}\n") {
+		t.Errorf("Got %q, want a trailing newline", wl.String())
+	}
+
+	m := regexp.MustCompile(`^\{"level":"info","time":"[^"]+","file":"caller","msg":"Test message"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Reset()
+	lg.InfofAt_Loc("remote.go", 123, "Test message")
+	m = regexp.MustCompile(`^\{"level":"info","time":"[^"]+","file":"remote.go","line":123,"msg":"Test message"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	lg.SetFormatter(nil)
+	il.Reset()
+	lg.Infof("Test message")
+	if s := il.String(); !imatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, imatcher)
+	}
+}
+
+func TestJSONFormatterMaxFields(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestJSONFormatterMaxFields")
+	lg.Info = il
+	lg.MaxFields = 2
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.WithFields(Fields{"a": 1, "b": 2, "c": 3, "d": 4}).Infof("Test message")
+
+	var got struct {
+		Fields  map[string]interface{}
+		Dropped int `json:"fields_dropped"`
+	}
+	if err := json.Unmarshal(il.Bytes(), &got); err != nil {
+		t.Fatalf("Got %v, want JSON from info log: %v", il.String(), err)
+	}
+	if len(got.Fields) != 2 || got.Fields["a"] != 1.0 || got.Fields["b"] != 2.0 || got.Dropped != 2 {
+		t.Errorf("Got fields %v and %d dropped, want a and b with 2 dropped", got.Fields, got.Dropped)
+	}
+}
+
+func TestSetTimeKey(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetTimeKey")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.SetTimeKey("@timestamp")
+	lg.SetLevelKey("severity")
+	lg.SetMessageKey("message")
+	lg.Infof("Test message")
+	m := regexp.MustCompile(`^\{"severity":"info","@timestamp":"[^"]+","file":"format_test.go","line":\d+,"message":"Test message"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Reset()
+	lg.SetTimeKey("")
+	lg.SetLevelKey("")
+	lg.SetMessageKey("")
+	lg.Infof("Test message")
+	m = regexp.MustCompile(`^\{"level":"info","time":"[^"]+",.*"msg":"Test message"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+// A field value with its own JSON encoding.
+type jsonPoint struct{ x, y int }
+
+func (p jsonPoint) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("[%d,%d]", p.x, p.y)), nil
+}
+
+func (p jsonPoint) String() string {
+	return fmt.Sprintf("(%d, %d)", p.x, p.y)
+}
+
+func TestJSONFormatterMarshaler(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestJSONFormatterMarshaler")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.WithFields(Fields{"at": jsonPoint{1, 2}}).Infof("Test message")
+	m := regexp.MustCompile(`"fields":\{"at":\[1,2\]\}`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+// A Stringer that cannot be called on a nil pointer.
+type jsonName struct{ first, last string }
+
+func (n *jsonName) String() string {
+	return n.first + " " + n.last
+}
+
+// An error that cannot be called on a nil pointer.
+type jsonError struct{ code int }
+
+func (e *jsonError) Error() string {
+	return fmt.Sprintf("error %d", e.code)
+}
+
+func TestJSONFormatterNil(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestJSONFormatterNil")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	var name *jsonName
+	var err *jsonError
+	lg.WithFields(Fields{"name": name, "err": err, "none": nil}).Infof("Test message")
+	m := regexp.MustCompile(`"fields":\{"err":null,"name":null,"none":null\}`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
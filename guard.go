@@ -0,0 +1,88 @@
+package log
+
+import "runtime/debug"
+
+// Guard runs fn, recovering from any panic it raises.
+// A recovered panic is logged at ERROR level, along with name and the stack of
+// the panic, and the value is returned rather than re-panicked so the caller can
+// decide whether to carry on.
+// Returns nil if fn did not panic.
+func (l *Logger) Guard(name string, fn func()) (recovered interface{}) {
+	return guard(l, name, fn)
+}
+
+// Guard runs fn, recovering from any panic it raises and logging it to the root
+// logger.
+// See Logger.Guard for details.
+func Guard(name string, fn func()) (recovered interface{}) {
+	return guard(Root, name, fn)
+}
+
+// Shared by both forms of Guard, so the call depth is the same for each.
+func guard(l *Logger, name string, fn func()) interface{} {
+	r, stack := try(fn)
+	if r != nil {
+		l.write(LevelError, l.e, l.calldepth+1, "%s panicked: %v\n%s", name, r, stack)
+	}
+	return r
+}
+
+// Recover logs a panic in flight at ERROR level, along with its stack, and
+// stops it, so the deferring function returns normally.
+// It must be called directly by defer:
+//
+//	defer lg.Recover()
+//
+// It does nothing if there is no panic.
+func (l *Logger) Recover() {
+	if r := recover(); r != nil {
+		logPanic(l, r, debug.Stack())
+	}
+}
+
+// Recover logs a panic in flight at ERROR level to the root logger, and stops
+// it.
+// See Logger.Recover for details.
+func Recover() {
+	if r := recover(); r != nil {
+		logPanic(Root, r, debug.Stack())
+	}
+}
+
+// RecoverAndRepanic logs a panic in flight at ERROR level, along with its stack,
+// and then panics again with the same value, for when the panic should still
+// crash the process.
+// It must be called directly by defer, and does nothing if there is no panic.
+func (l *Logger) RecoverAndRepanic() {
+	if r := recover(); r != nil {
+		logPanic(l, r, debug.Stack())
+		panic(r)
+	}
+}
+
+// RecoverAndRepanic logs a panic in flight at ERROR level to the root logger,
+// and then panics again with the same value.
+// See Logger.RecoverAndRepanic for details.
+func RecoverAndRepanic() {
+	if r := recover(); r != nil {
+		logPanic(Root, r, debug.Stack())
+		panic(r)
+	}
+}
+
+// Shared by the forms of Recover, so the call depth is the same for each.
+// The message is attributed to the caller of panic, above the runtime's frame.
+func logPanic(l *Logger, r interface{}, stack []byte) {
+	l.write(LevelError, l.e, l.calldepth+2, "Recovered panic: %v\n%s", r, stack)
+}
+
+// Runs fn, returning the value and stack of any panic it raises.
+func try(fn func()) (r interface{}, stack []byte) {
+	defer func() {
+		if r = recover(); r != nil {
+			stack = debug.Stack()
+		}
+	}()
+	fn()
+	return nil, nil
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestGuard")
+	lg.Error = el
+
+	if r := lg.Guard("quiet", func() {}); r != nil {
+		t.Errorf("Got %v, want nil from a function that did not panic", r)
+	}
+	if s := el.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from error log", s)
+	}
+
+	r := lg.Guard("callback", func() {
+		panic("Test message")
+	})
+	if r != "Test message" {
+		t.Errorf("Got %v, want the recovered panic value", r)
+	}
+	m := regexp.MustCompile(`^E.*guard_test.go:\d+: callback panicked: Test message
+(?s:.*)guard_test.go`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestRecover")
+	lg.Error = el
+
+	func() {
+		defer lg.Recover()
+	}()
+	if s := el.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from error log without a panic", s)
+	}
+
+	func() {
+		defer lg.Recover()
+		panic("Test message")
+	}()
+	m := regexp.MustCompile(`^E.*guard_test.go:\d+: Recovered panic: Test message
+(?s:.*)guard_test.go`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+
+	el.Reset()
+	var r interface{}
+	func() {
+		defer func() { r = recover() }()
+		defer lg.RecoverAndRepanic()
+		panic("Test message")
+	}()
+	if r != "Test message" {
+		t.Errorf("Got %v, want the panic to continue with its value", r)
+	}
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
@@ -0,0 +1,145 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultGzipFlushInterval is how often a GzipWriter flushes its compressed
+// stream, so that recent records reach the file (and survive a crash).
+const DefaultGzipFlushInterval = 5 * time.Second
+
+// GzipWriter is an io.Writer that gzips everything written to it on the fly.
+//
+// The stream is flushed every DefaultGzipFlushInterval, on Flush (including
+// from SetFlushEvery), and on Close.
+// A GzipWriter is safe for concurrent use.
+type GzipWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	gz *gzip.Writer
+
+	// For a GzipWriter from NewGzipFile, the path written, and the file open
+	// there; see ReopenWriter.
+	path string
+	fi   os.FileInfo
+
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	// Makes Close idempotent, keeping its first result.
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewGzipWriter returns a GzipWriter that writes compressed data to w.
+func NewGzipWriter(w io.Writer) *GzipWriter {
+	g := &GzipWriter{w: w, gz: gzip.NewWriter(w)}
+	g.start()
+	return g
+}
+
+// NewGzipFile returns a GzipWriter that appends compressed data to the file at
+// path (named like "service.log.gz"), creating it if necessary.
+// Like a ReopenWriter, it reopens the path whenever the file there is moved or
+// replaced, but first finishes the gzip stream in the old file, so that rotated
+// files are complete .gz files.
+func NewGzipFile(path string) (*GzipWriter, error) {
+	g := &GzipWriter{path: path}
+	if err := g.open(); err != nil {
+		return nil, err
+	}
+	g.start()
+	return g, nil
+}
+
+// Starts the periodic flushes.
+func (g *GzipWriter) start() {
+	g.done = make(chan struct{})
+	g.wg.Add(1)
+	go g.tick(DefaultGzipFlushInterval)
+}
+
+func (g *GzipWriter) tick(d time.Duration) {
+	defer g.wg.Done()
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-g.done:
+			return
+		case <-t.C:
+			g.Flush()
+		}
+	}
+}
+
+// Opens g.path and starts a new gzip stream there, finishing and closing any
+// file already open.
+func (g *GzipWriter) open() error {
+	f, err := os.OpenFile(g.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if g.gz != nil {
+		g.closeStream()
+	}
+	g.w, g.fi = f, fi
+	g.gz = gzip.NewWriter(f)
+	return nil
+}
+
+// Write compresses p into the stream.
+func (g *GzipWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.path != "" {
+		if fi, err := os.Stat(g.path); err != nil || !os.SameFile(fi, g.fi) {
+			if err := g.open(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return g.gz.Write(p)
+}
+
+// Flush writes any pending compressed data to the underlying writer.
+func (g *GzipWriter) Flush() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.gz.Flush()
+}
+
+// Close finishes the gzip stream, and closes the underlying writer if it is an
+// io.Closer.
+// Later calls do nothing, and return the result of the first.
+func (g *GzipWriter) Close() error {
+	g.closeOnce.Do(func() {
+		close(g.done)
+		g.wg.Wait()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.closeErr = g.closeStream()
+	})
+	return g.closeErr
+}
+
+// Finishes the gzip stream, and closes the underlying writer if it is an
+// io.Closer. g.mu must be held.
+func (g *GzipWriter) closeStream() error {
+	err := g.gz.Close()
+	if c, ok := g.w.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// Returns the decompressed contents of the gzip file at path.
+func gunzipFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open(%v) failed: %v", path, err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader(%v) failed: %v", path, err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Reading the gzip stream in %v failed: %v", path, err)
+	}
+	return string(b)
+}
+
+func TestGzipFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log.gz")
+	gw, err := NewGzipFile(path)
+	if err != nil {
+		t.Fatalf("NewGzipFile(%v) failed: %v", path, err)
+	}
+	lg := New("TestGzipFile")
+	lg.Info = gw
+	lg.Infof("Before %s", "rotation")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	lg.Infof("After rotation")
+	lg.Infof("Test message")
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	m := regexp.MustCompile(`^I.*gzip_test.go:\d+: Before rotation\n$`)
+	if s := gunzipFile(t, path+".1"); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from the rotated file", s, m)
+	}
+	m = regexp.MustCompile(`^I.*gzip_test.go:\d+: After rotation
+I.*gzip_test.go:\d+: Test message
+$`)
+	if s := gunzipFile(t, path); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from the new file", s, m)
+	}
+}
+
+func TestGzipWriterCloseTwice(t *testing.T) {
+	var b bytes.Buffer
+	gw := NewGzipWriter(&b)
+	gw.Write([]byte("Test message\n"))
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	n := b.Len()
+	if err := gw.Close(); err != nil {
+		t.Errorf("Got %v from the second Close, want nil like the first", err)
+	}
+	if b.Len() != n {
+		t.Errorf("Got %v bytes after the second Close, want %v as after the first", b.Len(), n)
+	}
+}
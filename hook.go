@@ -0,0 +1,43 @@
+package log
+
+import "sync"
+
+// Hook is told about every message a Logger writes, at any level, like for
+// counting messages per level in a metrics system without replacing the
+// writers.
+type Hook interface {
+	// Fire is called with each message, after it is formatted (including its
+	// fields) and written.
+	// It must be safe for concurrent use.
+	Fire(level Level, msg string) error
+}
+
+type hookList struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// AddHook makes the logger (and those derived from it) call h for every
+// message written.
+// Messages that are muted, dropped, or deduplicated are not written, so h does
+// not see them.
+// An error from h is written to the root logger's ERROR writer, bypassing its
+// hooks; it does not affect the message.
+func (l *Logger) AddHook(h Hook) {
+	l.shared.hooks.mu.Lock()
+	defer l.shared.hooks.mu.Unlock()
+	l.shared.hooks.hooks = append(l.shared.hooks.hooks, h)
+}
+
+// Calls each hook, reporting their errors as from depth frames up.
+func (hl *hookList) fire(level Level, depth int, msg string) {
+	hl.mu.RLock()
+	hooks := hl.hooks
+	hl.mu.RUnlock()
+	for _, h := range hooks {
+		if err := h.Fire(level, msg); err != nil {
+			text, e := Root.entry(LevelError, nil, "Hook failed on "+level.String()+" message: "+err.Error())
+			Root.output(Root.e, depth+1, text, e)
+		}
+	}
+}
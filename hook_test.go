@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+type countingHook struct {
+	mu     sync.Mutex
+	counts map[Level]int
+	err    error
+}
+
+func (h *countingHook) Fire(level Level, msg string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[level]++
+	return h.err
+}
+
+func TestAddHook(t *testing.T) {
+	il, wl, rl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	Root.Error = rl
+	lg := New("TestAddHook")
+	lg.Info, lg.Warn = il, wl
+
+	counter := &countingHook{counts: make(map[Level]int)}
+	lg.AddHook(counter)
+	lg.Infof("Test message")
+	lg.Warnf("Test message")
+	lg.Warnf("Test message")
+	if want := map[Level]int{LevelInfo: 1, LevelWarn: 2}; len(counter.counts) != 2 ||
+		counter.counts[LevelInfo] != want[LevelInfo] || counter.counts[LevelWarn] != want[LevelWarn] {
+		t.Errorf("Got counts %v, want %v", counter.counts, want)
+	}
+	if rl.Len() != 0 {
+		t.Errorf("Got %v from the root error log, want nothing from a working hook", rl.String())
+	}
+
+	lg.AddHook(&countingHook{counts: make(map[Level]int), err: errors.New("counter unavailable")})
+	lg.Infof("Test message")
+	m := regexp.MustCompile(`^I.*Test message
+I.*Test message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log despite a failing hook", s, m)
+	}
+	m = regexp.MustCompile(`^E.*hook_test.go:\d+: Hook failed on info message: counter unavailable
+$`)
+	if s := rl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from root error log", s, m)
+	}
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"net/http"
+	"time"
+)
+
+// LogRequest writes a standardized access-log record for an HTTP request.
+// The record carries the fields method, path, status, dur, remote, and ua.
+// Its level follows the status: ERROR for 5xx, WARN for 4xx, and INFO otherwise.
+func (l *Logger) LogRequest(r *http.Request, status int, dur time.Duration) {
+	logRequest(l, r, status, dur)
+}
+
+// LogRequest writes a standardized access-log record for an HTTP request to the
+// root logger.
+// See Logger.LogRequest for details.
+func LogRequest(r *http.Request, status int, dur time.Duration) {
+	logRequest(Root, r, status, dur)
+}
+
+// Shared by both forms of LogRequest, so the call depth is the same for each.
+func logRequest(l *Logger, r *http.Request, status int, dur time.Duration) {
+	f := Fields{
+		"method": r.Method,
+		"path":   r.URL.Path,
+		"status": status,
+		"dur":    dur,
+		"remote": r.RemoteAddr,
+		"ua":     r.UserAgent(),
+	}
+	switch {
+	case status >= 500:
+		l.writeFields(LevelError, l.e, l.calldepth+1, f, "")
+	case status >= 400:
+		l.writeFields(LevelWarn, l.w, l.calldepth+1, f, "")
+	default:
+		l.writeFields(LevelInfo, l.i, l.calldepth+1, f, "")
+	}
+}
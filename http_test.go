@@ -0,0 +1,39 @@
+package log
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLogRequest(t *testing.T) {
+	il, wl, el := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLogRequest")
+	lg.Info = il
+	lg.Warn = wl
+	lg.Error = el
+
+	r := httptest.NewRequest("POST", "/things?x=1", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("User-Agent", "tester/1.0")
+	lg.LogRequest(r, 500, 1500*time.Millisecond)
+
+	m := regexp.MustCompile("^E.*http_test.go:\\d+: dur=1.5s method=POST path=/things remote=10.0.0.1:1234 status=500 ua=tester/1.0\n$")
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+	if s := il.String() + wl.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info and warn logs", s)
+	}
+
+	lg.LogRequest(r, 404, time.Millisecond)
+	if s := wl.String(); !regexp.MustCompile("^W.* status=404 ").MatchString(s) {
+		t.Errorf("Got %v, want a 404 record from warn log", s)
+	}
+	lg.LogRequest(r, 200, time.Millisecond)
+	if s := il.String(); !regexp.MustCompile("^I.* status=200 ").MatchString(s) {
+		t.Errorf("Got %v, want a 200 record from info log", s)
+	}
+}
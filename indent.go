@@ -0,0 +1,21 @@
+package log
+
+import "sync/atomic"
+
+// Indent returns a derived logger whose messages are indented one level (two
+// spaces) deeper than this logger's, after the header.
+// Indenting a derived logger again nests further, which helps a human follow
+// hierarchical flows in text logs.
+// The returned dedent function puts the derived logger back at this logger's
+// indentation, for when it outlives the nested operation.
+//
+// The derived logger shares this logger's writers, verbosity, and state; set
+// writers on the original.
+func (l *Logger) Indent() (*Logger, func()) {
+	base := atomic.LoadInt32(&l.indent)
+	c := l.derive()
+	c.indent = base + 1
+	return c, func() {
+		atomic.StoreInt32(&c.indent, base)
+	}
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestIndent(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestIndent")
+	lg.Info = il
+
+	lg.Infof("Outer")
+	in, dedent := lg.Indent()
+	in.Infof("Middle")
+	inner, _ := in.Indent()
+	inner.Infof("Inner")
+	dedent()
+	in.Infof("Dedented")
+
+	m := regexp.MustCompile(`^I.*indent_test.go:\d+: Outer
+I.*indent_test.go:\d+:   Middle
+I.*indent_test.go:\d+:     Inner
+I.*indent_test.go:\d+: Dedented
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
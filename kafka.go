@@ -0,0 +1,174 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KafkaMessage is a single message to produce to Kafka.
+type KafkaMessage struct {
+	Key, Value []byte
+}
+
+// KafkaProducer sends batches of messages to a Kafka topic.
+// NewKafkaWriter provides one backed by a real client, when built with the
+// "kafka" tag; anything else (like a test fake) can be used with
+// NewKafkaProducerWriter.
+type KafkaProducer interface {
+	Produce(topic string, msgs []KafkaMessage) error
+	Close() error
+}
+
+// KafkaWriter is an io.Writer that produces each entry written to it as a JSON
+// message (as from JSONFormatter, fields included) to a Kafka topic.
+// It is an EntryWriter, so one KafkaWriter can be assigned to several levels.
+// Text written to it by other means is sent line by line as
+// `{"level":"info","msg":...}`.
+// Messages are sent in batches, when BatchSize are waiting or FlushInterval has
+// passed, whichever comes first.
+// Call Close to send anything still waiting.
+type KafkaWriter struct {
+	p     KafkaProducer
+	topic string
+
+	// KeyField, if set, names a field whose value becomes the message key
+	// (e.g. "request_id"), so related records land in the same partition.
+	// Set it before writing.
+	KeyField string
+
+	mu        sync.Mutex
+	batch     []KafkaMessage
+	batchSize int
+	err       error // The last error producing a batch, returned by the next Write.
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Default batching for KafkaWriter.
+const (
+	DefaultKafkaBatchSize     = 100
+	DefaultKafkaFlushInterval = time.Second
+)
+
+// NewKafkaProducerWriter returns a KafkaWriter that produces to topic through p,
+// with the default batch size and flush interval.
+func NewKafkaProducerWriter(p KafkaProducer, topic string) *KafkaWriter {
+	w := &KafkaWriter{
+		p:         p,
+		topic:     topic,
+		batchSize: DefaultKafkaBatchSize,
+		done:      make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.tick(DefaultKafkaFlushInterval)
+	return w
+}
+
+// SetBatchSize sets how many messages are sent at once.
+func (w *KafkaWriter) SetBatchSize(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	w.batchSize = n
+}
+
+func (w *KafkaWriter) tick(d time.Duration) {
+	defer w.wg.Done()
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-t.C:
+			w.Flush()
+		}
+	}
+}
+
+// WriteEntry queues e as a message, keyed by its KeyField field (if any), and
+// sends the batch if it is full.
+// Returns the error from the last failed send, if any since the previous write.
+func (w *KafkaWriter) WriteEntry(e Entry) error {
+	var key []byte
+	if v, ok := e.Fields[w.KeyField]; ok && w.KeyField != "" {
+		key = []byte(formatValue(v))
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queue(KafkaMessage{Key: key, Value: bytes.TrimSuffix(JSONFormatter{}.Format(e), []byte("\n"))})
+	return w.takeErr()
+}
+
+// Write queues each line of p as a message, sending the batch if it is full.
+// Returns the error from the last failed send, if any since the previous write.
+func (w *KafkaWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		rec := struct {
+			Level Level  `json:"level"`
+			Msg   string `json:"msg"`
+		}{LevelInfo, line}
+		v, err := json.Marshal(rec)
+		if err != nil {
+			return 0, err
+		}
+		w.queue(KafkaMessage{Value: v})
+	}
+	if err := w.takeErr(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Adds m to the batch, sending it if it is full. w.mu must be held.
+func (w *KafkaWriter) queue(m KafkaMessage) {
+	w.batch = append(w.batch, m)
+	if len(w.batch) >= w.batchSize {
+		w.send()
+	}
+}
+
+// Returns and clears the last error sending a batch. w.mu must be held.
+func (w *KafkaWriter) takeErr() error {
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Sends the waiting batch. w.mu must be held.
+func (w *KafkaWriter) send() {
+	if len(w.batch) == 0 {
+		return
+	}
+	if err := w.p.Produce(w.topic, w.batch); err != nil {
+		w.err = err
+	}
+	w.batch = nil
+}
+
+// Flush sends any waiting messages.
+func (w *KafkaWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.send()
+	return w.takeErr()
+}
+
+// Close sends any waiting messages, and closes the producer.
+func (w *KafkaWriter) Close() error {
+	close(w.done)
+	w.wg.Wait()
+	err := w.Flush()
+	if cerr := w.p.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
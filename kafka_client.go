@@ -0,0 +1,39 @@
+//go:build kafka
+
+package log
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// segmentioProducer is a KafkaProducer backed by github.com/segmentio/kafka-go.
+type segmentioProducer struct {
+	w *kafka.Writer
+}
+
+func (p segmentioProducer) Produce(topic string, msgs []KafkaMessage) error {
+	km := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		km[i] = kafka.Message{Key: m.Key, Value: m.Value}
+	}
+	return p.w.WriteMessages(context.Background(), km...)
+}
+
+func (p segmentioProducer) Close() error {
+	return p.w.Close()
+}
+
+// NewKafkaWriter returns a KafkaWriter that produces to topic on the given
+// brokers.
+// It is only available when built with the "kafka" tag, which keeps the Kafka
+// client out of ordinary builds.
+func NewKafkaWriter(brokers []string, topic string) (*KafkaWriter, error) {
+	kw := &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+	}
+	return NewKafkaProducerWriter(segmentioProducer{kw}, topic), nil
+}
@@ -0,0 +1,13 @@
+//go:build !kafka
+
+package log
+
+import "errors"
+
+// NewKafkaWriter returns a KafkaWriter that produces to topic on the given
+// brokers.
+// This build has no Kafka client, so it always fails; build with the "kafka"
+// tag for a real one, or use NewKafkaProducerWriter with a client of your own.
+func NewKafkaWriter(brokers []string, topic string) (*KafkaWriter, error) {
+	return nil, errors.New("log: built without Kafka support; build with -tags kafka")
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type fakeProducer struct {
+	topics  []string
+	batches [][]KafkaMessage
+	closed  bool
+	err     error
+}
+
+func (f *fakeProducer) Produce(topic string, msgs []KafkaMessage) error {
+	f.topics = append(f.topics, topic)
+	f.batches = append(f.batches, append([]KafkaMessage(nil), msgs...))
+	return f.err
+}
+
+func (f *fakeProducer) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestKafkaWriter(t *testing.T) {
+	fp := &fakeProducer{}
+	kw := NewKafkaProducerWriter(fp, "logs")
+	kw.SetBatchSize(2)
+	kw.KeyField = "request_id"
+	lg := New("TestKafkaWriter")
+	lg.Info = kw
+	lg.Warn = kw
+
+	lg.WithFields(Fields{"request_id": "abc"}).Infof("One")
+	lg.WithFields(Fields{"user": "ann"}).Warnf("Two")
+	lg.Infof("Three request_id=xyz")
+	if len(fp.batches) != 1 || len(fp.batches[0]) != 2 {
+		t.Fatalf("Got batches %v, want one batch of two before Close", fp.batches)
+	}
+	if err := kw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(fp.batches) != 2 || len(fp.batches[1]) != 1 || !fp.closed {
+		t.Fatalf("Got batches %v (closed %v), want the last message sent and the producer closed", fp.batches, fp.closed)
+	}
+	for _, topic := range fp.topics {
+		if topic != "logs" {
+			t.Errorf("Got topic %v, want logs", topic)
+		}
+	}
+
+	if key := fp.batches[0][0].Key; string(key) != "abc" {
+		t.Errorf("Got key %q, want abc from the request_id field", key)
+	}
+	if key := fp.batches[1][0].Key; key != nil {
+		t.Errorf("Got key %q, want none from message text", key)
+	}
+	var rec struct {
+		Level  Level  `json:"level"`
+		Msg    string `json:"msg"`
+		File   string `json:"file"`
+		Fields Fields `json:"fields"`
+	}
+	if err := json.Unmarshal(fp.batches[0][1].Value, &rec); err != nil {
+		t.Fatalf("Got invalid JSON %s: %v", fp.batches[0][1].Value, err)
+	}
+	if rec.Level != LevelWarn || rec.Msg != "Two" || rec.File != "kafka_test.go" || rec.Fields["user"] != "ann" {
+		t.Errorf("Got %+v, want the warning with its user field", rec)
+	}
+
+	fp = &fakeProducer{err: errors.New("broker down")}
+	kw = NewKafkaProducerWriter(fp, "logs")
+	kw.SetBatchSize(1)
+	if _, err := kw.Write([]byte("I message\n")); err == nil {
+		t.Errorf("Got nil error, want the failed send reported by Write")
+	}
+	kw.Close()
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLap(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestLap")
+	lg.Info = il
+	lg.SetVerbosity(LapVerbosity)
+
+	req := lg.WithFields(nil)
+	req.Lap("start")
+	time.Sleep(10 * time.Millisecond)
+	req.Lap("slept")
+
+	m := regexp.MustCompile(`^I.*lap_test.go:\d+: Lap start: \+.*
+I.*lap_test.go:\d+: Lap slept: \+(\S+) \(total (\S+)\)
+$`)
+	s := il.String()
+	sm := m.FindStringSubmatch(s)
+	if sm == nil {
+		t.Fatalf("Got %v, want something matching %v from info log", s, m)
+	}
+	if d, err := time.ParseDuration(sm[1]); err != nil || d < 10*time.Millisecond {
+		t.Errorf("Got a delta of %v (err %v), want at least 10ms", sm[1], err)
+	}
+	if d, err := time.ParseDuration(sm[2]); err != nil || d < 10*time.Millisecond {
+		t.Errorf("Got a total of %v (err %v), want at least 10ms", sm[2], err)
+	}
+
+	il.Reset()
+	lg.SetVerbosity(0)
+	lg.Lap("quiet")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log below LapVerbosity", s)
+	}
+}
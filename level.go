@@ -0,0 +1,91 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is the severity of a log message.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+
+	// Audit events are kept apart from the ordinary levels; see Logger.Audit.
+	levelAudit
+
+	numLevels
+)
+
+var levelNames = [numLevels]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelFatal: "fatal",
+	levelAudit: "audit",
+}
+
+// String returns the lowercase name of the level, like "warn".
+func (lv Level) String() string {
+	if lv < 0 || lv >= numLevels {
+		return "unknown"
+	}
+	return levelNames[lv]
+}
+
+// MarshalText encodes the level as its name, so it reads well in JSON.
+func (lv Level) MarshalText() ([]byte, error) {
+	return []byte(lv.String()), nil
+}
+
+// UnmarshalText decodes a level from its name.
+func (lv *Level) UnmarshalText(b []byte) error {
+	for l, name := range levelNames {
+		if string(b) == name {
+			*lv = Level(l)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %q", b)
+}
+
+// ParseLevel returns the level named by s, ignoring case: "debug", "info",
+// "warn" (or "warning"), "error" (or "err"), or "fatal".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error", "err":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// SetMinLevel makes the logger discard messages below level, a coarser control
+// than the numeric verbosity of V.
+// Fatal messages and audit events are always written, and SetLevelFloor can
+// raise the floor of what is written above level.
+// The default is LevelDebug, which discards nothing.
+func (l *Logger) SetMinLevel(level Level) {
+	if level > LevelFatal {
+		level = LevelFatal
+	}
+	atomic.StoreInt32(&l.shared.minLevel, int32(level))
+}
+
+// Returns whether level is below the logger's SetMinLevel.
+func (l *Logger) belowMin(level Level) bool {
+	return int32(level) < atomic.LoadInt32(&l.shared.minLevel)
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"Warn":    LevelWarn,
+		"warning": LevelWarn,
+		"err":     LevelError,
+		"error":   LevelError,
+		" fatal ": LevelFatal,
+	} {
+		if got, err := ParseLevel(s); err != nil || got != want {
+			t.Errorf("Got %v (err %v) from ParseLevel(%q), want %v", got, err, s, want)
+		}
+	}
+	for _, s := range []string{"", "audit", "verbose"} {
+		if got, err := ParseLevel(s); err == nil {
+			t.Errorf("Got %v from ParseLevel(%q), want an error", got, s)
+		}
+	}
+}
+
+func TestSetMinLevel(t *testing.T) {
+	il, wl, el := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetMinLevel")
+	lg.Info = il
+	lg.Warn = wl
+	lg.Error = el
+
+	lg.SetMinLevel(LevelWarn)
+	lg.Infof("Dropped")
+	lg.Warnf("Test message")
+	lg.Errorf("Test message")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log below the minimum", s)
+	}
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, wmatcher)
+	}
+	if s := el.String(); !ematcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, ematcher)
+	}
+
+	lg.SetMinLevel(LevelError)
+	SetLevelFloor(LevelWarn)
+	defer SetLevelFloor(-1)
+	wl.Reset()
+	lg.Warnf("Test message")
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log at the floor", s, wmatcher)
+	}
+}
@@ -0,0 +1,47 @@
+package log
+
+// The field and mapping from SetLevelByField.
+type levelMapping struct {
+	field   string
+	mapping map[string]Level
+}
+
+// SetLevelByField makes the value of the named field decide the level of each
+// record that has it, overriding the level it was logged at, e.g.
+//
+//	lg.SetLevelByField("severity", map[string]log.Level{
+//	  "critical": log.LevelFatal,
+//	  "major":    log.LevelError,
+//	})
+//
+// Values are compared as they render in text, and records without the field,
+// or with an unmapped value, keep their level.
+// Audit events always go to the AuditTrail.
+//
+// Beware that a record raised to LevelFatal this way calls Exit, just as if it
+// were logged by Fatalf. A record from Fatalf or Panicf that is mapped to a
+// lower level is written at that level, but still exits or panics.
+// A nil or empty mapping turns this off, which is the default.
+func (l *Logger) SetLevelByField(field string, mapping map[string]Level) {
+	m := levelMapping{field: field, mapping: make(map[string]Level, len(mapping))}
+	for k, v := range mapping {
+		if v >= LevelDebug && v <= LevelFatal {
+			m.mapping[k] = v
+		}
+	}
+	l.shared.levelMapping.Store(m)
+}
+
+// Returns the level that fields map to by SetLevelByField, if any.
+func (l *Logger) levelByField(fields Fields) (Level, bool) {
+	m, _ := l.shared.levelMapping.Load().(levelMapping)
+	if len(m.mapping) == 0 {
+		return 0, false
+	}
+	v, ok := fields[m.field]
+	if !ok {
+		return 0, false
+	}
+	lv, ok := m.mapping[formatValue(v)]
+	return lv, ok
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestSetLevelByField(t *testing.T) {
+	il, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetLevelByField")
+	lg.Info = il
+	lg.Error = el
+	lg.Fatal = fl
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.SetLevelByField("severity", map[string]Level{
+		"major":    LevelError,
+		"critical": LevelFatal,
+	})
+	lg.Log("Disk nearly full", Field("severity", "major"))
+	lg.Log("Disk filling", Field("severity", "minor"))
+	if exited {
+		t.Errorf("Got a call to Exit, want none before a critical record")
+	}
+	lg.WithFields(Fields{"severity": "critical"}).Infof("Disk full")
+
+	m := regexp.MustCompile(`^E.*levelfield_test.go:\d+: Disk nearly full severity=major\n$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+	m = regexp.MustCompile(`^I.*levelfield_test.go:\d+: Disk filling severity=minor\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	m = regexp.MustCompile(`^F.*levelfield_test.go:\d+: Disk full severity=critical\n$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+	if !exited {
+		t.Errorf("Got no call to Exit, want one after a record raised to fatal")
+	}
+}
+
+func TestSetLevelByFieldAudit(t *testing.T) {
+	el, al := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetLevelByFieldAudit")
+	lg.Error = el
+	lg.AuditTrail = al
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.SetLevelByField("result", map[string]Level{
+		"denied": LevelError,
+		"failed": LevelFatal,
+	})
+	lg.Audit("login", Fields{"actor": "alice", "result": "denied"})
+	lg.Audit("login", Fields{"actor": "bob", "result": "failed"})
+
+	m := regexp.MustCompile(`^A.*levelfield_test.go:\d+: action=login actor=alice result=denied
+A.*levelfield_test.go:\d+: action=login actor=bob result=failed
+$`)
+	if s := al.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from audit log", s, m)
+	}
+	if s := el.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from error log", s)
+	}
+	if exited {
+		t.Errorf("Got a call to Exit, want none for an audit event")
+	}
+}
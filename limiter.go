@@ -0,0 +1,29 @@
+package log
+
+// Limiter decides whether a message may be written, for throttling logs with a
+// shared policy (e.g. a *rate.Limiter from golang.org/x/time/rate).
+type Limiter interface {
+	Allow() bool
+}
+
+// Wraps a Limiter, so that an atomic.Value always holds the same type.
+type limiterHolder struct {
+	Limiter
+}
+
+// SetLimiter makes the logger consult lim before writing each INFO, WARN, or
+// ERROR message, dropping (and counting; see Dropped) those it does not allow.
+// Fatal messages and audit events are never limited.
+// A nil lim turns limiting off, which is the default.
+func (l *Logger) SetLimiter(lim Limiter) {
+	l.shared.limiter.Store(limiterHolder{lim})
+}
+
+// Returns whether a message at the given level may be written.
+func (l *Logger) allow(level Level) bool {
+	if level >= LevelFatal {
+		return true
+	}
+	h, _ := l.shared.limiter.Load().(limiterHolder)
+	return h.Limiter == nil || h.Allow()
+}
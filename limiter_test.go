@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type fakeLimiter bool
+
+func (f fakeLimiter) Allow() bool { return bool(f) }
+
+func TestSetLimiter(t *testing.T) {
+	il, fl := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetLimiter")
+	lg.Info = il
+	lg.Fatal = fl
+	lg.Exit = nil
+
+	lg.SetLimiter(fakeLimiter(false))
+	lg.Infof("Dropped")
+	lg.Infof("Dropped")
+	lg.Fatalf("Test message")
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from limited info log", s)
+	}
+	if s := fl.String(); !fmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from unlimited fatal log", s, fmatcher)
+	}
+	if got := lg.Dropped()[LevelInfo]; got != 2 {
+		t.Errorf("Got %v dropped info records, want 2", got)
+	}
+
+	lg.SetLimiter(fakeLimiter(true))
+	lg.Infof("Test message")
+	lg.SetLimiter(nil)
+	lg.Infof("Test message")
+	if s := il.String(); strings.Count(s, "Test message\n") != 2 {
+		t.Errorf("Got %v, want two messages from info log once allowed", s)
+	}
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InfofList writes a single INFO-level message listing at most max of the items,
+// comma-separated after prefix, with a note of how many more were left out.
+// For example: "Pending: a, b, c ...and 7 more".
+func (l *Logger) InfofList(items []interface{}, max int, prefix string) {
+	l.write(LevelInfo, l.i, l.calldepth, "%s", formatList(items, max, prefix))
+}
+
+// InfofList writes a single INFO-level message to the root logger listing at
+// most max of the items.
+// See Logger.InfofList for details.
+func InfofList(items []interface{}, max int, prefix string) {
+	Root.write(LevelInfo, Root.i, Root.calldepth, "%s", formatList(items, max, prefix))
+}
+
+func formatList(items []interface{}, max int, prefix string) string {
+	if max < 0 {
+		max = 0
+	}
+	shown := items
+	if len(shown) > max {
+		shown = shown[:max]
+	}
+	strs := make([]string, len(shown))
+	for i, item := range shown {
+		strs[i] = fmt.Sprint(item)
+	}
+	msg := prefix + ": " + strings.Join(strs, ", ")
+	if more := len(items) - len(shown); more > 0 {
+		msg += fmt.Sprintf(" ...and %d more", more)
+	}
+	return msg
+}
@@ -0,0 +1,27 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestInfofList(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestInfofList")
+	lg.Info = il
+
+	items := make([]interface{}, 10)
+	for i := range items {
+		items[i] = i
+	}
+	lg.InfofList(items, 3, "Pending")
+	lg.InfofList(items[:2], 3, "Short")
+
+	m := regexp.MustCompile(`^I.*list_test.go:\d+: Pending: 0, 1, 2 \.\.\.and 7 more
+I.*list_test.go:\d+: Short: 0, 1
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
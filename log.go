@@ -10,31 +10,59 @@
 package log
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
 	Verbosity = flag.Int("verbosity", 0, "Logging verbosity level. Higher means more logs.")
 	Root      *Logger
+
+	// Replaced in tests.
+	osExit = os.Exit
 )
 
 // The rewriter type allows us to change the destination of written data without
 // rebuilding the actual log.Logger objects used.
+// If route holds a function (see SetWriterFunc), it gets first say in where the
+// data goes.
+// The destination is read under mu, so that SetInfo and friends may swap it
+// while others log.
 type rewriter struct {
-	w *io.Writer
+	w     *io.Writer
+	route *atomic.Value
+	level Level
+	mu    *sync.RWMutex
 }
 
 func (w *rewriter) Write(p []byte) (int, error) {
-	return (*w.w).Write(p)
+	return w.dest().Write(p)
+}
+
+// Returns the writer that writes currently go to.
+func (w *rewriter) dest() io.Writer {
+	if w.route != nil {
+		if f, _ := w.route.Load().(func(Level) io.Writer); f != nil {
+			if dst := f(w.level); dst != nil {
+				return dst
+			}
+		}
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return *w.w
 }
 
 func init() {
 	Root = New("")
+	verbosityFromEnv(os.LookupEnv)
 }
 
 // Logable is the interface required for writing data to the next lower level.
@@ -43,16 +71,168 @@ type Logable interface {
 	Output(calldepth int, s string) error
 }
 
+// shared holds the mutable state of a Logger that its derived loggers (e.g. from
+// Indent) share with it.
+type shared struct {
+	// Records written, and how often (in records) to flush the writers.
+	// Accessed atomically.
+	records, flushEvery int64
+
+	// Records written, and dropped by the Limiter, per level.
+	// Accessed atomically.
+	counts, dropped [numLevels]int64
+
+	// Holds the limiterHolder from SetLimiter.
+	limiter atomic.Value
+
+	// Rates from SetSampleRateFor.
+	sample sampleState
+
+	// Messages sampled out, kept for SetSampleContext.
+	sampledOut sampledOut
+
+	// Temporary verbosity from BoostVerbosity.
+	boost verbosityBoost
+
+	// Error types already logged by ErrorfOncePerType.
+	errTypes errorTypeSet
+
+	// Holds the func(Level) io.Writer from SetWriterFunc.
+	route atomic.Value
+
+	// Guards the writer fields of the Logger (and its derived loggers) against
+	// SetInfo and friends.
+	writersMu sync.RWMutex
+
+	// Holds the func(context.Context) Fields from SetContextExtractor.
+	extractor atomic.Value
+
+	// Holds the levelMapping from SetLevelByField.
+	levelMapping atomic.Value
+
+	// Holds the formatterHolder from SetFormatter.
+	formatter atomic.Value
+
+	// Holds the layout string from SetTimeFormat.
+	timeFormat atomic.Value
+
+	// Holds the revision string from SetCommit.
+	commit atomic.Value
+
+	// Holds the version string from SetSchemaVersion.
+	schema atomic.Value
+
+	// Hold the key strings from SetTimeKey, SetLevelKey, and SetMessageKey.
+	timeKey, levelKey, msgKey atomic.Value
+
+	// Serializes writing records, as log.Logger does.
+	outMu sync.Mutex
+
+	// Outstanding MuteLevel calls per level. Accessed atomically.
+	muted [numLevels]int32
+
+	// The level from SetMinLevel. Accessed atomically.
+	minLevel int32
+
+	// Collapses repeated messages; see SetFuzzyDedup.
+	dedup dedupState
+
+	// Suppresses repeated fingerprints; see SetFingerprintCooldown.
+	cooldown cooldownState
+
+	// Recently written records; see CaptureRecent.
+	ring ring
+
+	// Holds the path string from SetCrashFile.
+	crashFile atomic.Value
+
+	// Holds the int from SetExitCode.
+	exitCode atomic.Value
+
+	// Non-zero when DevInfof messages are written. Accessed atomically.
+	dev int32
+
+	// Non-zero after LogSummaryOnExit. Accessed atomically.
+	summaryOnExit int32
+
+	// Call rates of VAdaptive call sites.
+	sites siteRates
+
+	// Last writes of InfofEvery (etc.) call sites.
+	every everyState
+
+	// Fields marked by MarkPII.
+	pii piiState
+
+	// Hooks from AddHook.
+	hooks hookList
+}
+
 // Logger provides an individually configurable logging instance.
 type Logger struct {
 	name      string
 	calldepth int
 
+	// State shared with derived loggers.
+	shared *shared
+
+	// Levels of indentation applied to messages; see Indent.
+	// Accessed atomically.
+	indent int32
+
+	// Where relative paths given to TrySetFile are resolved; see InDir.
+	dir string
+
+	// Whether to skip the limiter; see InfofCtx.
+	unlimited bool
+
+	// The stack to write after the next message's fields; see withStack.
+	trace string
+
+	// The caller to name in place of the real one, and its line if known; see
+	// InfofFrom and InfofAt_Loc.
+	loc     string
+	locLine int
+
+	// Fields appended to every message; see WithFields.
+	context Fields
+
+	// Whether the logger is from NewTest, which renders fields for reading.
+	test bool
+
+	// The name looked up in --vmodule; see Named.
+	module string
+
+	// The dotted name from Sub, shown before each message.
+	sub string
+
+	// The logger whose writer fields the Logables write through: this one,
+	// or the one it was derived from. Nil for loggers without such Logables.
+	owner *Logger
+
+	// Times Lap calls. Each derived logger gets its own.
+	lap *lapTimer
+
 	// Verbosity indicates how "loud" this logger is.
 	// It defaults to the Verbosity flag.
 	Verbosity *int
 
-	i, w, e, f Logable
+	d, i, w, e, f, a Logable
+
+	// Info-level output without the computed file:line, for callers that supply
+	// their own.
+	ic Logable
+
+	// The writers below may be assigned directly while nothing is logging, as
+	// at startup; use SetInfo and friends to change them while other goroutines
+	// may be.
+	// A derived logger (like one from WithFields or Sub) writes through the
+	// writers of the logger it was derived from, so assigning its own fields
+	// has no effect; its SetInfo and friends change the original's.
+
+	// Debug is where all DEBUG-level messages get written.
+	// It defaults to io.Discard, so debug output is off unless set.
+	Debug io.Writer
 
 	// Info is where all INFO-level messages get written.
 	Info io.Writer
@@ -66,31 +246,115 @@ type Logger struct {
 	// Fatal is where all FATAL-level messages get written.
 	Fatal io.Writer
 
+	// AuditTrail is where all audit events get written.
+	// It is kept separate from the leveled writers so the audit trail is never
+	// mixed with ordinary logs.
+	// (It cannot be named Audit, since that is the method that writes to it.)
+	AuditTrail io.Writer
+
+	// Diagnostics is where the logger reports its own problems, like failed
+	// writes. If nil, they go to the standard log package's logger.
+	Diagnostics io.Writer
+
+	// OnWriteError, if set, is called with the level and error of each failed
+	// write (like a full disk), instead of reporting it to Diagnostics, so the
+	// application can react, e.g. by switching to another writer.
+	// It must be safe for concurrent use.
+	OnWriteError func(level Level, err error)
+
+	// MaxFields caps how many fields are written with a message, to bound the
+	// size of records; extras are dropped (keeping the first by sorted key) and
+	// their number noted.
+	// Zero means no limit. Audit events are never capped.
+	MaxFields int
+
+	// UTC makes the timestamps of text records UTC, rather than local time.
+	UTC bool
+
+	// OmitTime leaves the timestamp out of text records, for destinations that
+	// add their own (like syslog; see NewSyslogWriter).
+	OmitTime bool
+
+	// Encode, if set, transforms each rendered record (without its trailing
+	// newline) before it is written, like EncodeBase64 for transports that
+	// mangle some bytes.
+	// A newline is written after the result.
+	Encode func([]byte) []byte
+
+	// Color makes the level letters of text records colored (e.g. yellow for
+	// WARN) when they are written to a terminal, unless the NO_COLOR
+	// environment variable is set.
+	// Records written elsewhere are unaffected.
+	Color bool
+
+	// IncludeStack makes Errorf and Panicf append the stack of the goroutine
+	// that called them, starting at their caller, beneath the message and its
+	// fields (or as the stack of the Entry, for a Formatter).
+	IncludeStack bool
+
+	// StackDepth limits the stacks from IncludeStack to their top frames, to
+	// keep records bounded.
+	// Zero means no limit.
+	StackDepth int
+
+	// DetectSecrets masks things that look like secrets (AWS keys, JWTs,
+	// password=... and the like, and card numbers) in every message, marking
+	// those it changes with a _redacted=true field.
+	// It is a heuristic safety net, off by default since it can mask innocent
+	// text.
+	DetectSecrets bool
+
 	// Exit is the function to call after logging a Fatal message.
 	// If nil, is not called.
+	// The default exits the process with the status from SetExitCode.
 	Exit func()
+
+	// FatalfRequiresExit makes a Fatal message exit the process (with the
+	// status from SetExitCode) when Exit is nil, rather than return, so that a
+	// misconfigured logger still terminates.
+	// It defaults to false, so that tests may set Exit to nil.
+	FatalfRequiresExit bool
+
+	// FatalGracePeriod is how long a Fatal message waits for the logger's
+	// writers to be flushed (see Fatalf) before calling Exit.
+	// If flushing takes longer, Exit is called anyway.
+	// Zero (the default) waits for flushing however long it takes.
+	FatalGracePeriod time.Duration
 }
 
 // New returns a new Logger with the given name.
 func New(name string) *Logger {
 	l := &Logger{
-		name:      name,
-		calldepth: 3,
-		Verbosity: Verbosity,
-		Info:      os.Stderr,
-		Warn:      os.Stderr,
-		Error:     os.Stderr,
-		Fatal:     os.Stderr,
-		Exit:      func() { os.Exit(1) },
-	}
-	flags := log.Ldate | log.Ltime | log.Lshortfile
-	l.i = log.New(&rewriter{&l.Info}, "I", flags)
-	l.w = log.New(&rewriter{&l.Warn}, "W", flags)
-	l.e = log.New(&rewriter{&l.Error}, "E", flags)
-	l.f = log.New(&rewriter{&l.Fatal}, "F", flags)
+		name:       name,
+		calldepth:  3,
+		shared:     new(shared),
+		Verbosity:  Verbosity,
+		Debug:      io.Discard,
+		Info:       os.Stderr,
+		Warn:       os.Stderr,
+		Error:      os.Stderr,
+		Fatal:      os.Stderr,
+		AuditTrail: os.Stderr,
+		lap:        newLapTimer(),
+	}
+	l.Exit = func() { osExit(l.ExitCode()) }
+	l.bind(log.Ldate | log.Ltime | log.Lshortfile)
 	return l
 }
 
+// Builds the logger's Logables to write to its own writer fields, with the
+// given standard log package flags.
+func (l *Logger) bind(flags int) {
+	l.owner = l
+	l.d = log.New(&rewriter{&l.Debug, &l.shared.route, LevelDebug, &l.shared.writersMu}, "D", flags)
+	l.i = log.New(&rewriter{&l.Info, &l.shared.route, LevelInfo, &l.shared.writersMu}, "I", flags)
+	l.w = log.New(&rewriter{&l.Warn, &l.shared.route, LevelWarn, &l.shared.writersMu}, "W", flags)
+	l.e = log.New(&rewriter{&l.Error, &l.shared.route, LevelError, &l.shared.writersMu}, "E", flags)
+	l.f = log.New(&rewriter{&l.Fatal, &l.shared.route, LevelFatal, &l.shared.writersMu}, "F", flags)
+	l.a = log.New(&rewriter{w: &l.AuditTrail, mu: &l.shared.writersMu}, "A", flags)
+	l.ic = log.New(&rewriter{&l.Info, &l.shared.route, LevelInfo, &l.shared.writersMu}, "I", flags&^log.Lshortfile)
+}
+
 // A type that translates io.Writer.Write() calls into testing.T.Logf/Errorf/Fatalf()-like calls
 type testWriter struct {
 	f func(format string, v ...interface{})
@@ -106,6 +370,11 @@ func testLog(level string, f func(format string, v ...interface{})) *log.Logger
 	return log.New(testWriter{f}, level, log.Lmicroseconds|log.Lshortfile)
 }
 
+// Like testLog, but leaves out the file:line of the caller.
+func testLogNoCaller(level string, f func(format string, v ...interface{})) *log.Logger {
+	return log.New(testWriter{f}, level, log.Lmicroseconds)
+}
+
 // TestLogable provides access to testing.T-type logging functions.
 type TestLogable interface {
 	Logf(format string, v ...interface{})
@@ -122,8 +391,12 @@ func NewTest(t TestLogable, name string, failOnError bool) *Logger {
 	l := &Logger{
 		name:      name,
 		calldepth: 3,
+		shared:    new(shared),
 		Verbosity: Verbosity,
+		test:      true,
+		lap:       newLapTimer(),
 	}
+	l.d = testLog("D", t.Logf)
 	l.i = testLog("I", t.Logf)
 	l.w = testLog("W", t.Logf)
 	if failOnError {
@@ -132,13 +405,82 @@ func NewTest(t TestLogable, name string, failOnError bool) *Logger {
 		l.e = testLog("E", t.Logf)
 	}
 	l.f = testLog("F", t.Fatalf)
+	l.a = testLog("A", t.Logf)
+	l.ic = testLogNoCaller("I", t.Logf)
 	return l
 }
 
+// Returns a copy of the logger, sharing its writers and state.
+func (l *Logger) derive() *Logger {
+	c := *l
+	c.lap = newLapTimer()
+	return &c
+}
+
 func (l *Logger) Name() string {
 	return l.name
 }
 
+// Returns the address of the writer field for the given level, or nil for an
+// unknown level.
+// For a derived logger, this is the field of the logger it was derived from,
+// which is the one its messages are written through.
+func (l *Logger) writerFor(level Level) *io.Writer {
+	o := l.own()
+	switch level {
+	case LevelDebug:
+		return &o.Debug
+	case LevelInfo:
+		return &o.Info
+	case LevelWarn:
+		return &o.Warn
+	case LevelError:
+		return &o.Error
+	case LevelFatal:
+		return &o.Fatal
+	case levelAudit:
+		return &o.AuditTrail
+	}
+	return nil
+}
+
+// Returns the logger whose writer fields this one writes through.
+func (l *Logger) own() *Logger {
+	if l.owner != nil {
+		return l.owner
+	}
+	return l
+}
+
+// Returns the Logable for the given level, falling back to ERROR for an unknown
+// level.
+func (l *Logger) logable(level Level) Logable {
+	switch level {
+	case LevelDebug:
+		return l.d
+	case LevelInfo:
+		return l.i
+	case LevelWarn:
+		return l.w
+	case LevelFatal:
+		return l.f
+	case levelAudit:
+		return l.a
+	}
+	return l.e
+}
+
+// SetWriterFunc makes the logger ask f where each record should go, based on
+// its level.
+// f is consulted on every write, so it must be cheap and safe for concurrent
+// use; if it returns nil, the record goes to the level's usual writer (Info,
+// Warn, etc.).
+// A nil f turns routing off.
+// Audit events are never routed, and loggers from NewTest ignore f.
+func (l *Logger) SetWriterFunc(f func(level Level) io.Writer) {
+	l.shared.route.Store(f)
+}
+
 // SetVerbosity is a convenience method to set the logging verbosity to a constant.
 func (l *Logger) SetVerbosity(v int) {
 	l.Verbosity = &v
@@ -146,19 +488,84 @@ func (l *Logger) SetVerbosity(v int) {
 
 // Formats the message and writes it to the given logger.
 // Returns the formatted message.
-// If there is an error writing to the given logger, writes a description
-// including the given message to the base logger.
-func write(l Logable, depth int, name, format string, v ...interface{}) string {
-	msg := fmt.Sprintf(format, v...)
-	if err := l.Output(depth, msg); err != nil {
-		log.Printf("Failed to write to %s logger: %v.\n  Message: %s", name, err, msg)
+// If there is an error writing to the given logger, passes it to OnWriteError,
+// or failing that writes a description including the given message to the
+// Diagnostics writer.
+func (l *Logger) write(level Level, lg Logable, depth int, format string, v ...interface{}) string {
+	return l.writeFields(level, lg, depth+1, nil, format, v...)
+}
+
+// Like write, but attaches fields to the message, along with any from
+// WithFields.
+// The returned message includes the fields, as rendered for text.
+func (l *Logger) writeFields(level Level, lg Logable, depth int, fields Fields, format string, v ...interface{}) string {
+	text, e := l.entry(level, fields, fmt.Sprintf(format, v...))
+	msg := text
+	if lv, ok := l.levelByField(e.Fields); ok && lv != level && level != levelAudit {
+		if lv == LevelFatal {
+			defer func() { l.exit(msg) }()
+		}
+		level, lg, e.Level = lv, l.logable(lv), lv
+	}
+	if (atomic.LoadInt32(&l.shared.muted[level]) > 0 || l.belowMin(level)) && !aboveFloor(level) {
+		return msg
+	}
+	if !l.unlimited && !aboveFloor(level) && !l.allow(level) {
+		atomic.AddInt64(&l.shared.dropped[level], 1)
+		return msg
+	}
+	if !l.unlimited && !aboveFloor(level) && !l.shared.sample.keep(level) {
+		l.shared.sampledOut.add(l, level, lg, depth+1, text, e)
+		return msg
+	}
+	if l.shared.dedup.suppress(l, level, lg, depth+1, msg) {
+		return msg
+	}
+	if level != levelAudit {
+		if ok, n := l.shared.cooldown.allow(e.Fields); !ok {
+			return msg
+		} else if n > 0 {
+			e.Fields = e.Fields.copy()
+			e.Fields["suppressed"] = n
+			text = l.renderEntry(e)
+			msg = text
+		}
+	}
+	if level >= LevelError && level != levelAudit {
+		l.shared.sampledOut.flush(depth + 1)
+	}
+	return l.emit(level, lg, depth+1, text, e)
+}
+
+// Writes a record that has passed the filters of writeFields, and does the
+// bookkeeping that follows (hooks, counts, and so on).
+// depth is as for output, called from this function.
+// Returns the message, with its fields but without indentation.
+func (l *Logger) emit(level Level, lg Logable, depth int, text string, e Entry) string {
+	msg := text
+	if n := atomic.LoadInt32(&l.indent); n > 0 {
+		indent := strings.Repeat("  ", int(n))
+		text, e.Message = indent+text, indent+e.Message
+	}
+	if err := l.output(lg, depth, text, e); err != nil {
+		if l.OnWriteError != nil {
+			l.OnWriteError(level, err)
+		} else {
+			l.diagf("Failed to write to %s %s logger: %v.\n  Message: %s", l.name, level, err, msg)
+		}
+	}
+	l.shared.hooks.fire(level, depth, msg)
+	atomic.AddInt64(&l.shared.counts[level], 1)
+	l.shared.ring.add(level, msg)
+	if n := atomic.LoadInt64(&l.shared.flushEvery); n > 0 && atomic.AddInt64(&l.shared.records, 1)%n == 0 {
+		l.flush()
 	}
 	return msg
 }
 
 // LoudEnough returns whether the verbosity is high enough to include messages of the given level.
 func (l *Logger) LoudEnough(level int) bool {
-	return level <= *l.Verbosity
+	return level <= l.verbosity() || l.shared.boost.covers(level)
 }
 
 // LoudEnough returns whether the verbosity on the root logger is high enough to include messages of the given level.
@@ -166,86 +573,229 @@ func LoudEnough(level int) bool {
 	return Root.LoudEnough(level)
 }
 
+// Debugf writes log messages at DEBUG level.
+// They go to the Debug writer, which discards them unless set.
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.write(LevelDebug, l.d, l.calldepth, format, v...)
+}
+
+// Debugf writes log messages at DEBUG level to the root logger.
+func Debugf(format string, v ...interface{}) {
+	Root.write(LevelDebug, Root.d, Root.calldepth, format, v...)
+}
+
 // V writes log messages at INFO level, but only if the configured verbosity is equal or greater than the provided level.
 func (l *Logger) V(level int, format string, v ...interface{}) {
 	if l.LoudEnough(level) {
-		write(l.i, l.calldepth, l.name+" info", format, v...)
+		l.write(LevelInfo, l.i, l.calldepth, format, v...)
 	}
 }
 
 // V writes log messages at INFO level to the root logger, but only if the configured verbosity is equal or greater than the provided level.
 func V(level int, format string, v ...interface{}) {
 	if Root.LoudEnough(level) {
-		write(Root.i, Root.calldepth, Root.name+" info", format, v...)
+		Root.write(LevelInfo, Root.i, Root.calldepth, format, v...)
+	}
+}
+
+// VFunc writes the message returned by f at INFO level, but only if the
+// configured verbosity is equal or greater than the provided level.
+// f is not called otherwise, so expensive messages cost nothing when not
+// logged.
+func (l *Logger) VFunc(level int, f func() string) {
+	if l.LoudEnough(level) {
+		l.write(LevelInfo, l.i, l.calldepth, "%s", f())
+	}
+}
+
+// VFunc writes the message returned by f at INFO level to the root logger, but
+// only if the configured verbosity is equal or greater than the provided level.
+func VFunc(level int, f func() string) {
+	if Root.LoudEnough(level) {
+		Root.write(LevelInfo, Root.i, Root.calldepth, "%s", f())
 	}
 }
 
 // Infof writes log messages at INFO level.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	write(l.i, l.calldepth, l.name+" info", format, v...)
+	l.write(LevelInfo, l.i, l.calldepth, format, v...)
 }
 
 // Infof writes log messages at INFO level to the root logger.
 func Infof(format string, v ...interface{}) {
-	write(Root.i, Root.calldepth, Root.name+" info", format, v...)
+	Root.write(LevelInfo, Root.i, Root.calldepth, format, v...)
+}
+
+// InfofFrom writes log messages at INFO level, naming caller in place of the
+// file:line that would otherwise be computed from the stack.
+// This is for generated code or dispatchers, where the logical caller (say, an
+// RPC handler name) is more meaningful than the real one.
+func (l *Logger) InfofFrom(caller string, format string, v ...interface{}) {
+	l.at(caller, 0).write(LevelInfo, l.ic, l.calldepth, format, v...)
+}
+
+// InfofFrom writes log messages at INFO level to the root logger, naming caller
+// in place of the file:line that would otherwise be computed from the stack.
+func InfofFrom(caller string, format string, v ...interface{}) {
+	Root.at(caller, 0).write(LevelInfo, Root.ic, Root.calldepth, format, v...)
+}
+
+// Returns a logger that names file (and line, if not zero) as the caller of
+// its messages.
+func (l *Logger) at(file string, line int) *Logger {
+	c := l.derive()
+	c.loc, c.locLine = file, line
+	return c
+}
+
+// InfofAt_Loc writes log messages at INFO level, using the given file and line
+// in place of those that would otherwise be computed from the stack.
+// This is for proxies and re-emitters that forward records from elsewhere and
+// know where they originally came from.
+func (l *Logger) InfofAt_Loc(file string, line int, format string, v ...interface{}) {
+	l.at(file, line).write(LevelInfo, l.ic, l.calldepth, format, v...)
+}
+
+// InfofAt_Loc writes log messages at INFO level to the root logger, using the
+// given file and line in place of those that would otherwise be computed from
+// the stack.
+func InfofAt_Loc(file string, line int, format string, v ...interface{}) {
+	Root.at(file, line).write(LevelInfo, Root.ic, Root.calldepth, format, v...)
 }
 
 // Printf is synonymous with Infof.
 // It exists for compatibility with the basic log package.
 func (l *Logger) Printf(format string, v ...interface{}) {
-	write(l.i, l.calldepth, l.name+" info", format, v...)
+	l.write(LevelInfo, l.i, l.calldepth, format, v...)
 }
 
 // Printf is synonymous with Infof.
 // It exists for compatibility with the basic log package.
 func Printf(format string, v ...interface{}) {
-	write(Root.i, Root.calldepth, Root.name+" info", format, v...)
+	Root.write(LevelInfo, Root.i, Root.calldepth, format, v...)
 }
 
 // Warnf writes log messages at WARN level.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	write(l.w, l.calldepth, l.name+" warn", format, v...)
+	l.write(LevelWarn, l.w, l.calldepth, format, v...)
 }
 
 // Warnf writes log messages at WARN level to the root logger.
 func Warnf(format string, v ...interface{}) {
-	write(Root.w, Root.calldepth, Root.name+" warn", format, v...)
+	Root.write(LevelWarn, Root.w, Root.calldepth, format, v...)
 }
 
 // Errorf writes log messages at ERROR level.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	write(l.e, l.calldepth, l.name+" error", format, v...)
+	l.withStack(1).write(LevelError, l.e, l.calldepth, format, v...)
 }
 
 // Errorf writes log messages at ERROR level to the root logger.
 func Errorf(format string, v ...interface{}) {
-	write(Root.e, Root.calldepth, Root.name+" error", format, v...)
+	Root.withStack(1).write(LevelError, Root.e, Root.calldepth, format, v...)
 }
 
 // Panicf writes log messages at ERROR level, and then panics.
-// The panic parameter is an error with the formatted message.
+// The message is tagged with a short correlation ID, like "[ref=1a2b3c4d]".
+// The panic parameter is a *PanicValue with the formatted message, that ID, and
+// the caller, so a handler can show the user a reference to the log entry.
 func (l *Logger) Panicf(format string, v ...interface{}) {
-	panic(errors.New(write(l.e, l.calldepth, l.name+" error", format, v...)))
+	msg, ref := fmt.Sprintf(format, v...), newID()
+	l.withStack(1).write(LevelError, l.e, l.calldepth, "[ref=%s] %s", ref, msg)
+	panic(newPanicValue(format, msg, ref))
 }
 
 // Panicf writes log messages at ERROR level to the root logger, and then panics.
-// The panic parameter is an error with the formatted message.
+// The panic parameter is a *PanicValue; see Logger.Panicf.
 func Panicf(format string, v ...interface{}) {
-	panic(errors.New(write(Root.e, Root.calldepth, Root.name+" error", format, v...)))
+	msg, ref := fmt.Sprintf(format, v...), newID()
+	Root.withStack(1).write(LevelError, Root.e, Root.calldepth, "[ref=%s] %s", ref, msg)
+	panic(newPanicValue(format, msg, ref))
+}
+
+// PanicfValue writes log messages at ERROR level, and then panics with val.
+// This is for integrations that recover a specific sentinel or type.
+func (l *Logger) PanicfValue(val interface{}, format string, v ...interface{}) {
+	l.write(LevelError, l.e, l.calldepth, format, v...)
+	panic(val)
+}
+
+// PanicfValue writes log messages at ERROR level to the root logger, and then
+// panics with val.
+func PanicfValue(val interface{}, format string, v ...interface{}) {
+	Root.write(LevelError, Root.e, Root.calldepth, format, v...)
+	panic(val)
 }
 
 // Fatalf writes log messages at FATAL level, and then calls Exit.
+// The message is tagged with a short correlation ID, like "[ref=1a2b3c4d]".
+// Before Exit, the logger's writers with a `Flush() error` method (like
+// bufio.Writer and AsyncWriter) are flushed, and those with a `Sync() error`
+// method (like os.File) synced, so that nothing buffered is lost.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-	write(l.f, l.calldepth, l.name+" fatal", format, v...)
-	if l.Exit != nil {
-		l.Exit()
-	}
+	l.exit(l.write(LevelFatal, l.f, l.calldepth, "[ref=%s] %s", newID(), fmt.Sprintf(format, v...)))
 }
 
 // Fatalf writes log messages at FATAL level to the root logger, and then calls Exit.
+// The message is tagged with a short correlation ID, like "[ref=1a2b3c4d]".
 func Fatalf(format string, v ...interface{}) {
-	write(Root.f, Root.calldepth, Root.name+" fatal", format, v...)
-	if Root.Exit != nil {
-		Root.Exit()
+	Root.exit(Root.write(LevelFatal, Root.f, Root.calldepth, "[ref=%s] %s", newID(), fmt.Sprintf(format, v...)))
+}
+
+// LogFatal writes log messages at FATAL level, like Fatalf, but returns rather
+// than calling Exit, for when the caller has cleanup to do before exiting.
+// The message is tagged with a short correlation ID, like "[ref=1a2b3c4d]".
+func (l *Logger) LogFatal(format string, v ...interface{}) {
+	l.write(LevelFatal, l.f, l.calldepth, "[ref=%s] %s", newID(), fmt.Sprintf(format, v...))
+}
+
+// LogFatal writes log messages at FATAL level to the root logger, but returns
+// rather than calling Exit.
+// See Logger.LogFatal for details.
+func LogFatal(format string, v ...interface{}) {
+	Root.write(LevelFatal, Root.f, Root.calldepth, "[ref=%s] %s", newID(), fmt.Sprintf(format, v...))
+}
+
+// Logf writes log messages at the given level, for when the level is only known
+// at run time (like when mapping another library's levels onto these).
+// It behaves like the method for that level: Debugf, Infof, Warnf, Errorf, or
+// Fatalf, which calls Exit.
+// An unknown level is logged at ERROR level.
+func (l *Logger) Logf(level Level, format string, v ...interface{}) {
+	l.logf(level, l.calldepth+1, format, v...)
+}
+
+// Logf writes log messages at the given level to the root logger.
+// See Logger.Logf.
+func Logf(level Level, format string, v ...interface{}) {
+	Root.logf(level, Root.calldepth+1, format, v...)
+}
+
+func (l *Logger) logf(level Level, depth int, format string, v ...interface{}) {
+	switch level {
+	case LevelDebug, LevelInfo, LevelWarn:
+		l.write(level, l.logable(level), depth, format, v...)
+	case LevelFatal:
+		l.exit(l.write(LevelFatal, l.f, depth, "[ref=%s] %s", newID(), fmt.Sprintf(format, v...)))
+	default:
+		l.withStack(2).write(LevelError, l.e, depth, format, v...)
+	}
+}
+
+// Does everything that follows writing a fatal message, ending with Exit.
+func (l *Logger) exit(msg string) {
+	l.writeCrashFile(msg)
+	if atomic.LoadInt32(&l.shared.summaryOnExit) != 0 && (l.Exit != nil || l.FatalfRequiresExit) {
+		l.LogSummary()
+	}
+	if l.FatalGracePeriod > 0 {
+		l.flushWithin(l.FatalGracePeriod)
+	} else {
+		l.flushAll()
+	}
+	if l.Exit != nil {
+		l.Exit()
+	} else if l.FatalfRequiresExit {
+		osExit(l.ExitCode())
 	}
 }
@@ -3,7 +3,10 @@ package log
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -141,6 +144,46 @@ $`)
 	}
 }
 
+func TestSetWriterFunc(t *testing.T) {
+	il, el, special := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetWriterFunc")
+	lg.Info = il
+	lg.Error = el
+
+	divert := false
+	lg.SetWriterFunc(func(level Level) io.Writer {
+		if divert && level == LevelError {
+			return special
+		}
+		return nil
+	})
+
+	lg.Errorf("Test message")
+	divert = true
+	lg.Errorf("Test message")
+	lg.Infof("Test message")
+
+	for _, c := range []struct {
+		name string
+		b    *bytes.Buffer
+		m    *regexp.Regexp
+	}{
+		{"error", el, ematcher},
+		{"special", special, ematcher},
+		{"info", il, imatcher},
+	} {
+		if s := c.b.String(); !c.m.MatchString(s) {
+			t.Errorf("Got %v, want something matching %v from %s log", s, c.m, c.name)
+		}
+	}
+
+	lg.SetWriterFunc(nil)
+	lg.Errorf("Test message")
+	if s := special.String(); strings.Count(s, "\n") != 1 {
+		t.Errorf("Got %v, want nothing more in the special log after routing is turned off", s)
+	}
+}
+
 func TestV(t *testing.T) {
 	il, wl, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
 	Root.Info = il
@@ -165,6 +208,33 @@ func TestV(t *testing.T) {
 	}
 }
 
+func TestDebug(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestDebug")
+	lg.Info = il
+	lg.Debugf("Test %s", "message")
+	if lg.Debug != io.Discard {
+		t.Errorf("Got %v, want io.Discard for the default debug writer", lg.Debug)
+	}
+	if m := il.String(); len(m) > 0 {
+		t.Errorf("Got %v, want empty from info log", m)
+	}
+
+	dl := new(bytes.Buffer)
+	Root.Debug = dl
+	defer func() { Root.Debug = io.Discard }()
+	m := regexp.MustCompile("^D.*log_test.go:\\d+: Test message\n$")
+	Debugf("Test %s", "message")
+	if s := dl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from debug log", s, m)
+	}
+	dl.Truncate(0)
+	Root.Debugf("Test %s", "message")
+	if s := dl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from debug log", s, m)
+	}
+}
+
 func TestInfo(t *testing.T) {
 	il, wl, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
 	Root.Info = il
@@ -187,6 +257,36 @@ func TestInfo(t *testing.T) {
 	}
 }
 
+func TestInfoFrom(t *testing.T) {
+	il := new(bytes.Buffer)
+	Root.Info = il
+
+	InfofFrom("Handler.Get", "Test %s", "message")
+	m := regexp.MustCompile(`^I\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} Handler\.Get: Test message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Truncate(0)
+	Root.InfofFrom("Handler.Put", "Test %s", "message")
+	if s := il.String(); !strings.HasSuffix(s, " Handler.Put: Test message\n") || strings.Contains(s, "log_test.go") {
+		t.Errorf("Got %v, want Handler.Put in place of file:line from info log", s)
+	}
+}
+
+func TestInfoAtLoc(t *testing.T) {
+	il := new(bytes.Buffer)
+	Root.Info = il
+
+	InfofAt_Loc("remote.go", 123, "Test %s", "message")
+	m := regexp.MustCompile(`^I\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} remote\.go:123: Test message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
 func TestPrint(t *testing.T) {
 	il, wl, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
 	Root.Info = il
@@ -260,11 +360,10 @@ func TestPanic(t *testing.T) {
 	Root.Error = el
 	Root.Fatal = fl
 
-	var err interface{}
 	done := make(chan struct{})
 	go func() {
 		defer func() {
-			err = recover()
+			recover()
 			close(done)
 		}()
 		Panicf("Test %s", "message")
@@ -285,6 +384,27 @@ func TestPanic(t *testing.T) {
 	}
 }
 
+func TestPanicValue(t *testing.T) {
+	el := new(bytes.Buffer)
+	Root.Error = el
+
+	type sentinel struct{ code int }
+	var r interface{}
+	func() {
+		defer func() {
+			r = recover()
+		}()
+		PanicfValue(sentinel{42}, "Test %s", "message")
+	}()
+
+	if r != (sentinel{42}) {
+		t.Errorf("Got %v, want the custom panic value", r)
+	}
+	if m := el.String(); !ematcher.MatchString(m) {
+		t.Errorf("Got %v, want something matching %v from error log", m, ematcher)
+	}
+}
+
 func TestFatal(t *testing.T) {
 	il, wl, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
 	Root.Info = il
@@ -318,6 +438,106 @@ func TestFatal(t *testing.T) {
 	Fatalf("The program should not crash here")
 }
 
+func TestFatalfRequiresExit(t *testing.T) {
+	defer func() { osExit = os.Exit }()
+	code := -1
+	osExit = func(c int) { code = c }
+
+	lg := New("TestFatalfRequiresExit")
+	lg.Fatal = new(bytes.Buffer)
+	lg.Exit = nil
+	lg.Fatalf("Test message")
+	if code != -1 {
+		t.Errorf("Got exit status %v, want no exit with Exit nil", code)
+	}
+
+	lg.FatalfRequiresExit = true
+	lg.Fatalf("Test message")
+	if code != 1 {
+		t.Errorf("Got exit status %v, want 1 with Exit nil and FatalfRequiresExit", code)
+	}
+
+	code = -1
+	called := false
+	lg.Exit = func() { called = true }
+	lg.Fatalf("Test message")
+	if !called || code != -1 {
+		t.Errorf("Got Exit called %v and exit status %v, want only Exit called when set", called, code)
+	}
+}
+
+func TestVFunc(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestVFunc")
+	lg.Info = il
+	lg.SetVerbosity(1)
+
+	calls := 0
+	f := func() string {
+		calls++
+		return "Test message"
+	}
+	lg.VFunc(2, f)
+	if calls != 0 || il.Len() != 0 {
+		t.Errorf("Got %v calls and %v from info log, want neither below the verbosity", calls, il)
+	}
+	lg.VFunc(1, f)
+	if calls != 1 {
+		t.Errorf("Got %v calls, want 1 at the verbosity", calls)
+	}
+	if s := il.String(); !imatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, imatcher)
+	}
+}
+
+func TestLogFatal(t *testing.T) {
+	fl := new(bytes.Buffer)
+	lg := New("TestLogFatal")
+	lg.Fatal = fl
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.LogFatal("Test %s", "message")
+	if exited {
+		t.Errorf("Got Exit called, want LogFatal to return without exiting")
+	}
+	m := regexp.MustCompile(`^F.*log_test.go:\d+: \[ref=\w+\] Test message\n$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+}
+
+func TestLogf(t *testing.T) {
+	il, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLogf")
+	lg.Info, lg.Error, lg.Fatal = il, el, fl
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.Logf(LevelInfo, "Test %s", "message")
+	lg.Logf(Level(99), "Unknown level")
+	if exited {
+		t.Errorf("Got Exit called before any FATAL message")
+	}
+	lg.Logf(LevelFatal, "Test message")
+	if !exited {
+		t.Errorf("Got Exit not called after a FATAL message")
+	}
+
+	m := regexp.MustCompile(`^I.*log_test.go:\d+: Test message\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	m = regexp.MustCompile(`^E.*log_test.go:\d+: Unknown level\n$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+	m = regexp.MustCompile(`^F.*log_test.go:\d+: \[ref=\w+\] Test message\n$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+}
+
 type fakeTest struct {
 	TestLogable
 	info  *bytes.Buffer
@@ -370,7 +590,7 @@ $`)
 	}
 
 	fatal := regexp.MustCompile(
-		`^F\d{2}:\d{2}:\d{2}\.\d{6} log_test.go:\d+: Fatal log
+		`^F\d{2}:\d{2}:\d{2}\.\d{6} log_test.go:\d+: \[ref=[0-9a-f]{8}\] Fatal log
 $`)
 	if s := ft.fatal.String(); !fatal.MatchString(s) {
 		t.Errorf("Got %v, want something matching %v from fatal log", s, fatal)
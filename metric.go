@@ -0,0 +1,29 @@
+package log
+
+// Metric writes a metrics-style event at INFO level, so that simple setups can
+// carry metrics over the logging transport.
+// The record is marked with a `_metric=true` field for a downstream processor to
+// split metrics from logs, and carries the metric name and value as the
+// `metric` and `value` fields, along with the tags.
+// Tags named _metric, metric, or value are overridden.
+func (l *Logger) Metric(name string, value float64, tags Fields) {
+	metric(l, name, value, tags)
+}
+
+// Metric writes a metrics-style event at INFO level to the root logger.
+// See Logger.Metric for details.
+func Metric(name string, value float64, tags Fields) {
+	metric(Root, name, value, tags)
+}
+
+// Shared by both forms of Metric, so the call depth is the same for each.
+func metric(l *Logger, name string, value float64, tags Fields) {
+	f := make(Fields, len(tags)+3)
+	for k, v := range tags {
+		f[k] = v
+	}
+	f["_metric"] = true
+	f["metric"] = name
+	f["value"] = value
+	l.writeFields(LevelInfo, l.i, l.calldepth+1, f, "")
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestMetric(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestMetric")
+	lg.Info = il
+
+	lg.Metric("requests", 42.5, Fields{"host": "a", "value": "ignored"})
+	m := regexp.MustCompile(`^I.*metric_test.go:\d+: _metric=true host=a metric=requests value=42.5
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	il.Truncate(0)
+	lg.MaxFields = 2
+	lg.Metric("requests", 1, Fields{"host": "a", "zone": "b"})
+	m = regexp.MustCompile(`: _metric=true host=a …\(\+3 fields dropped\)
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,25 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MuteLevel silences the given level until the returned function is called.
+// Other levels are unaffected, so (for example) warnings from a noisy library
+// can be hidden during a known-noisy operation without losing errors.
+// Mutes nest: the level stays silent until every MuteLevel on it is restored.
+// Muted messages are discarded, though Panicf still panics and Fatalf still
+// calls Exit.
+func (l *Logger) MuteLevel(level Level) (restore func()) {
+	if level < 0 || level >= numLevels {
+		return func() {}
+	}
+	atomic.AddInt32(&l.shared.muted[level], 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&l.shared.muted[level], -1)
+		})
+	}
+}
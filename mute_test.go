@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMuteLevel(t *testing.T) {
+	wl, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestMuteLevel")
+	lg.Warn = wl
+	lg.Error = el
+
+	restore := lg.MuteLevel(LevelWarn)
+	lg.Warnf("Muted message")
+	lg.Errorf("Test message")
+	if s := wl.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from muted warn log", s)
+	}
+	if s := el.String(); !ematcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, ematcher)
+	}
+
+	restore()
+	restore() // A second call must not unmute anything else.
+	lg.Warnf("Test message")
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from restored warn log", s, wmatcher)
+	}
+}
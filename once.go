@@ -0,0 +1,71 @@
+package log
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// errorTypeSet records which error types have been seen.
+type errorTypeSet struct {
+	mu   sync.Mutex
+	seen map[reflect.Type]bool
+}
+
+// Adds the type to the set, returning whether it was newly added.
+func (s *errorTypeSet) add(t reflect.Type) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[t] {
+		return false
+	}
+	if s.seen == nil {
+		s.seen = make(map[reflect.Type]bool)
+	}
+	s.seen[t] = true
+	return true
+}
+
+func (s *errorTypeSet) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen = nil
+}
+
+// Returns the type of the innermost error wrapped by err.
+func rootErrorType(err error) reflect.Type {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return reflect.TypeOf(err)
+		}
+		err = next
+	}
+}
+
+// ErrorfOncePerType writes log messages at ERROR level, but only for the first
+// error of each distinct type, until ResetErrorTypes is called.
+// The type is taken from the innermost error in err's wrap chain, so the same
+// underlying failure is recognized however it was wrapped.
+// This is meant for retry loops that would otherwise log the same category of
+// failure over and over.
+func (l *Logger) ErrorfOncePerType(err error, format string, v ...interface{}) {
+	if l.shared.errTypes.add(rootErrorType(err)) {
+		l.write(LevelError, l.e, l.calldepth, format, v...)
+	}
+}
+
+// ErrorfOncePerType writes log messages at ERROR level to the root logger, but
+// only for the first error of each distinct type.
+// See Logger.ErrorfOncePerType for details.
+func ErrorfOncePerType(err error, format string, v ...interface{}) {
+	if Root.shared.errTypes.add(rootErrorType(err)) {
+		Root.write(LevelError, Root.e, Root.calldepth, format, v...)
+	}
+}
+
+// ResetErrorTypes forgets which error types ErrorfOncePerType has logged, so
+// each will be logged once more.
+func (l *Logger) ResetErrorTypes() {
+	l.shared.errTypes.reset()
+}
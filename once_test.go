@@ -0,0 +1,40 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+
+func TestErrorfOncePerType(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestErrorfOncePerType")
+	lg.Error = el
+
+	// Both are timeouts underneath, however they are wrapped.
+	timeout := &os.PathError{Op: "read", Path: "a", Err: timeoutError{}}
+	lg.ErrorfOncePerType(timeout, "First timeout: %v", timeout)
+	lg.ErrorfOncePerType(errors.New("boom"), "First errorString")
+	lg.ErrorfOncePerType(fmt.Errorf("wrapped: %w", timeoutError{}), "Second timeout")
+	lg.ErrorfOncePerType(errors.New("bang"), "Second errorString")
+
+	if got := strings.Count(el.String(), "\n"); got != 2 {
+		t.Errorf("Got %v lines, want 2 from error log:\n%v", got, el)
+	}
+	if s := el.String(); strings.Contains(s, "Second") {
+		t.Errorf("Got %v, want only the first error of each type from error log", s)
+	}
+
+	lg.ResetErrorTypes()
+	lg.ErrorfOncePerType(timeout, "After reset")
+	if s := el.String(); !strings.HasSuffix(s, "After reset\n") {
+		t.Errorf("Got %v, want the error logged again after a reset", s)
+	}
+}
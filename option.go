@@ -0,0 +1,59 @@
+package log
+
+// Option adjusts a single call to Log; see At, Field, and Err.
+type Option func(*call)
+
+// The settings of a single call to Log.
+type call struct {
+	level  Level
+	fields Fields
+}
+
+// At makes the call log at the given level instead of INFO.
+// LevelFatal calls Exit afterward, like Fatalf.
+func At(level Level) Option {
+	return func(c *call) {
+		if level >= LevelDebug && level <= LevelFatal {
+			c.level = level
+		}
+	}
+}
+
+// Field adds the key=value field to the call's message.
+func Field(key string, value interface{}) Option {
+	return func(c *call) {
+		c.fields[key] = value
+	}
+}
+
+// Err adds err to the call's message, as the "error" field.
+func Err(err error) Option {
+	return Field("error", err)
+}
+
+// Log writes msg at INFO level, as adjusted by opts, e.g.
+//
+//	lg.Log("Retrying", log.At(log.LevelWarn), log.Field("attempt", n), log.Err(err))
+//
+// Fields are appended to msg in sorted key=value form.
+func (l *Logger) Log(msg string, opts ...Option) {
+	logCall(l, msg, opts)
+}
+
+// Log writes msg at INFO level to the root logger, as adjusted by opts.
+// See Logger.Log for details.
+func Log(msg string, opts ...Option) {
+	logCall(Root, msg, opts)
+}
+
+// Shared by both forms of Log, so the call depth is the same for each.
+func logCall(l *Logger, msg string, opts []Option) {
+	c := call{level: LevelInfo, fields: make(Fields)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	msg = l.writeFields(c.level, l.logable(c.level), l.calldepth+1, c.fields, "%s", msg)
+	if c.level == LevelFatal {
+		l.exit(msg)
+	}
+}
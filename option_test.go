@@ -0,0 +1,38 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	il, wl, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLog")
+	lg.Info = il
+	lg.Warn = wl
+	lg.Fatal = fl
+	exited := false
+	lg.Exit = func() { exited = true }
+
+	lg.Log("Plain")
+	lg.Log("Retrying", At(LevelWarn), Field("attempt", 2), Err(errors.New("timeout")))
+	lg.Log("Giving up", At(LevelFatal))
+
+	m := regexp.MustCompile(`^I.*option_test.go:\d+: Plain\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	m = regexp.MustCompile(`^W.*option_test.go:\d+: Retrying attempt=2 error=timeout\n$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+	m = regexp.MustCompile(`^F.*option_test.go:\d+: Giving up\n$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+	if !exited {
+		t.Errorf("Got no call to Exit, want one after logging at LevelFatal")
+	}
+}
@@ -0,0 +1,203 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLP severity numbers and text, from the OpenTelemetry log data model.
+var otlpSeverity = [numLevels]struct {
+	number int
+	text   string
+}{
+	LevelDebug: {5, "DEBUG"},
+	LevelInfo:  {9, "INFO"},
+	LevelWarn:  {13, "WARN"},
+	LevelError: {17, "ERROR"},
+	LevelFatal: {21, "FATAL"},
+	levelAudit: {9, "INFO"},
+}
+
+// otlpWriter exports each entry written to it as an OTLP log record, over
+// OTLP/HTTP with JSON encoding.
+type otlpWriter struct {
+	endpoint string
+	name     string
+	client   *http.Client
+}
+
+// OTLPExporter returns an io.Writer that exports every entry written to it as an
+// OpenTelemetry log record, by POSTing OTLP/HTTP JSON to endpoint (a full URL,
+// typically ending in /v1/logs).
+// Assign it to any of the logger's writers.
+//
+// The writer is an EntryWriter: the message becomes the body, the fields become
+// attributes, the severity is taken from the level, and the logger's name is
+// sent as the logger.name resource attribute.
+// Text written to it by other means is sent line by line as bodies, with no
+// severity.
+// Each write is a synchronous request; a failed export is returned as the
+// write's error.
+func (l *Logger) OTLPExporter(endpoint string) (io.Writer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: want an http or https URL", endpoint)
+	}
+	return &otlpWriter{
+		endpoint: endpoint,
+		name:     l.name,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// An OTLP AnyValue; exactly one member is set.
+// Integers are strings, as OTLP/JSON encodes 64-bit integers.
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    string   `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber,omitempty"`
+	SeverityText   string          `json:"severityText,omitempty"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope    `json:"scope"`
+	LogRecords []otlpRecord `json:"logRecords"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// Returns v as an OTLP value: numbers and bools as themselves, anything else
+// as its text form.
+func otlpAnyValue(v interface{}) otlpValue {
+	switch v := v.(type) {
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int:
+		return otlpValue{IntValue: strconv.FormatInt(int64(v), 10)}
+	case int8:
+		return otlpValue{IntValue: strconv.FormatInt(int64(v), 10)}
+	case int16:
+		return otlpValue{IntValue: strconv.FormatInt(int64(v), 10)}
+	case int32:
+		return otlpValue{IntValue: strconv.FormatInt(int64(v), 10)}
+	case int64:
+		return otlpValue{IntValue: strconv.FormatInt(v, 10)}
+	case uint8:
+		return otlpValue{IntValue: strconv.FormatUint(uint64(v), 10)}
+	case uint16:
+		return otlpValue{IntValue: strconv.FormatUint(uint64(v), 10)}
+	case uint32:
+		return otlpValue{IntValue: strconv.FormatUint(uint64(v), 10)}
+	case float32:
+		f := float64(v)
+		return otlpValue{DoubleValue: &f}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	}
+	return otlpString(formatValue(v))
+}
+
+func otlpString(s string) otlpValue {
+	return otlpValue{StringValue: &s}
+}
+
+func (w *otlpWriter) WriteEntry(e Entry) error {
+	r := otlpRecord{
+		TimeUnixNano: strconv.FormatInt(e.Time.UnixNano(), 10),
+		Body:         otlpString(e.Message),
+	}
+	if e.Level >= 0 && e.Level < numLevels {
+		sev := otlpSeverity[e.Level]
+		r.SeverityNumber, r.SeverityText = sev.number, sev.text
+	}
+	keys, _ := e.Fields.keys(0)
+	for _, k := range keys {
+		r.Attributes = append(r.Attributes, otlpAttribute{k, otlpAnyValue(e.Fields[k])})
+	}
+	if e.Stack != "" {
+		r.Attributes = append(r.Attributes, otlpAttribute{"exception.stacktrace", otlpString(e.Stack)})
+	}
+	if e.File != "" {
+		r.Attributes = append(r.Attributes,
+			otlpAttribute{"code.filepath", otlpString(e.File)},
+			otlpAttribute{"code.lineno", otlpAnyValue(e.Line)})
+	}
+	return w.export([]otlpRecord{r})
+}
+
+func (w *otlpWriter) Write(p []byte) (int, error) {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	var recs []otlpRecord
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		recs = append(recs, otlpRecord{TimeUnixNano: now, Body: otlpString(line)})
+	}
+	if err := w.export(recs); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sends the records to the collector in a single request.
+func (w *otlpWriter) export(recs []otlpRecord) error {
+	req := otlpRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{
+			Attributes: []otlpAttribute{{"logger.name", otlpString(w.name)}},
+		},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{"github.com/hegh/log"},
+			LogRecords: recs,
+		}},
+	}}}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP export to %s failed: %s", w.endpoint, resp.Status)
+	}
+	return nil
+}
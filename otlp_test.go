@@ -0,0 +1,75 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var got otlpRequest
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Collector got malformed JSON: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	lg := New("TestOTLPExporter")
+	if _, err := lg.OTLPExporter("not a url"); err == nil {
+		t.Errorf("Got nil error, want one for an invalid endpoint")
+	}
+	w, err := lg.OTLPExporter(srv.URL + "/v1/logs")
+	if err != nil {
+		t.Fatalf("OTLPExporter failed: %v", err)
+	}
+	lg.Warn = w
+	lg.WithFields(Fields{"user": "ann", "n": 2}).Warnf("Test %s", "message")
+
+	if path != "/v1/logs" {
+		t.Errorf("Got path %v, want /v1/logs", path)
+	}
+	if len(got.ResourceLogs) != 1 || len(got.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("Got %+v, want one resource and scope", got)
+	}
+	if attrs := got.ResourceLogs[0].Resource.Attributes; len(attrs) != 1 || attrs[0].Value.StringValue == nil || *attrs[0].Value.StringValue != "TestOTLPExporter" {
+		t.Errorf("Got resource attributes %+v, want logger.name=TestOTLPExporter", attrs)
+	}
+	recs := got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(recs) != 1 {
+		t.Fatalf("Got %v records, want 1", len(recs))
+	}
+	r := recs[0]
+	if r.SeverityNumber != 13 || r.SeverityText != "WARN" {
+		t.Errorf("Got severity %v %v, want 13 WARN", r.SeverityNumber, r.SeverityText)
+	}
+	if r.Body.StringValue == nil || *r.Body.StringValue != "Test message" || r.TimeUnixNano == "" {
+		t.Errorf("Got record %+v, want the message body and a timestamp", r)
+	}
+	attrs := make(map[string]otlpValue)
+	for _, a := range r.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	if v := attrs["user"]; v.StringValue == nil || *v.StringValue != "ann" {
+		t.Errorf("Got attributes %+v, want user=ann", r.Attributes)
+	}
+	if v := attrs["n"]; v.IntValue != "2" {
+		t.Errorf("Got attributes %+v, want n=2 as an int", r.Attributes)
+	}
+	if v := attrs["code.filepath"]; v.StringValue == nil || *v.StringValue != "otlp_test.go" {
+		t.Errorf("Got attributes %+v, want code.filepath=otlp_test.go", r.Attributes)
+	}
+
+	// Text written directly is sent as bodies, without a severity.
+	got = otlpRequest{}
+	if _, err := w.Write([]byte("first\nsecond\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	recs = got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(recs) != 2 || *recs[0].Body.StringValue != "first" || *recs[1].Body.StringValue != "second" || recs[0].SeverityNumber != 0 {
+		t.Errorf("Got records %+v, want first and second with no severity", recs)
+	}
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+)
+
+// PanicValue is the panic value of Panicf, for recovery code that wants more
+// than the message.
+type PanicValue struct {
+	// Message is the formatted message, without the correlation ID.
+	Message string
+
+	// Level is the level the message was logged at.
+	Level Level
+
+	// Format is the format string the message was formatted from.
+	Format string
+
+	// File and Line are the caller of Panicf (the file's base name only).
+	File string
+	Line int
+
+	// Ref is the correlation ID, as logged in "[ref=...]".
+	Ref string
+}
+
+// Error returns the formatted message, so code that prints the recovered error
+// is unaffected.
+func (p *PanicValue) Error() string {
+	return p.Message
+}
+
+// Returns the panic value of Panicf, whose caller is two frames above this
+// function's caller.
+func newPanicValue(format, msg, ref string) *PanicValue {
+	p := &PanicValue{Message: msg, Level: LevelError, Format: format, Ref: ref}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		p.File, p.Line = filepath.Base(file), line
+	}
+	return p
+}
+
+// AsPanicValue returns r as a *PanicValue, if it is one (or an error wrapping
+// one), for use with the result of recover:
+//
+//	defer func() {
+//	  if p, ok := log.AsPanicValue(recover()); ok {
+//	    ...
+//	  }
+//	}()
+func AsPanicValue(r interface{}) (*PanicValue, bool) {
+	switch v := r.(type) {
+	case *PanicValue:
+		return v, true
+	case error:
+		var p *PanicValue
+		if errors.As(v, &p) {
+			return p, true
+		}
+	}
+	return nil, false
+}
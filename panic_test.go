@@ -0,0 +1,45 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestAsPanicValue(t *testing.T) {
+	lg := New("TestAsPanicValue")
+	lg.Error = new(bytes.Buffer)
+
+	var r interface{}
+	func() {
+		defer func() {
+			r = recover()
+		}()
+		lg.Panicf("Test %s", "message")
+	}()
+
+	p, ok := AsPanicValue(r)
+	if !ok {
+		t.Fatalf("Got panic value %#v, want a *PanicValue", r)
+	}
+	if p.Error() != "Test message" || p.Message != "Test message" {
+		t.Errorf("Got %q (message %q), want %q from the panic value", p.Error(), p.Message, "Test message")
+	}
+	if p.Level != LevelError || p.Format != "Test %s" {
+		t.Errorf("Got level %v and format %q, want %v and %q", p.Level, p.Format, LevelError, "Test %s")
+	}
+	if p.File != "panic_test.go" || p.Line == 0 {
+		t.Errorf("Got caller %v:%v, want a line of panic_test.go", p.File, p.Line)
+	}
+	if m := regexp.MustCompile(`^[0-9a-f]{8}$`); !m.MatchString(p.Ref) {
+		t.Errorf("Got ref %q, want something matching %v", p.Ref, m)
+	}
+
+	if q, ok := AsPanicValue(fmt.Errorf("wrapped: %w", p)); !ok || q != p {
+		t.Errorf("Got %v (ok %v), want the wrapped panic value", q, ok)
+	}
+	if _, ok := AsPanicValue("not a panic value"); ok {
+		t.Errorf("Got ok for a string, want not ok")
+	}
+}
@@ -0,0 +1,33 @@
+package log
+
+import "strconv"
+
+// InfofPayload writes msg at INFO level with up to max bytes of payload (e.g. a
+// request body) attached as the `payload` field, quoted and escaped so that
+// binary data is safe to log.
+// If the payload is longer, the rest is left out, and a `payload_truncated`
+// field notes how many bytes were.
+func (l *Logger) InfofPayload(msg string, payload []byte, max int) {
+	l.writeFields(LevelInfo, l.i, l.calldepth, payloadFields(payload, max), "%s", msg)
+}
+
+// InfofPayload writes msg at INFO level to the root logger with up to max bytes
+// of payload attached.
+// See Logger.InfofPayload for details.
+func InfofPayload(msg string, payload []byte, max int) {
+	Root.writeFields(LevelInfo, Root.i, Root.calldepth, payloadFields(payload, max), "%s", msg)
+}
+
+// Returns the fields describing at most max bytes of payload.
+func payloadFields(payload []byte, max int) Fields {
+	if max < 0 {
+		max = 0
+	}
+	f := Fields{}
+	if len(payload) > max {
+		f["payload_truncated"] = len(payload) - max
+		payload = payload[:max]
+	}
+	f["payload"] = strconv.Quote(string(payload))
+	return f
+}
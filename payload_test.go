@@ -0,0 +1,23 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestInfofPayload(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestInfofPayload")
+	lg.Info = il
+
+	lg.InfofPayload("Request body", []byte("{\"name\":\"ann\"}\n\x00\xff and much more"), 16)
+	lg.InfofPayload("Short body", []byte("ok"), 16)
+
+	m := regexp.MustCompile(`^I.*payload_test.go:\d+: Request body payload="\{\\"name\\":\\"ann\\"\}\\n\\x00" payload_truncated=15
+I.*payload_test.go:\d+: Short body payload="ok"
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
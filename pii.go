@@ -0,0 +1,67 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// piiState holds the fields marked as PII, and whether to reveal them.
+type piiState struct {
+	mu     sync.RWMutex
+	keys   map[string]bool
+	reveal int32 // Accessed atomically.
+}
+
+// MarkPII marks the named fields as personally identifiable information.
+// Their values are masked in every record, unless RevealPII is on, and records
+// with any of them carry a _has_pii=true field either way, so that the same
+// code can satisfy both external compliance and internal debugging.
+func (l *Logger) MarkPII(keys ...string) {
+	p := &l.shared.pii
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys == nil {
+		p.keys = make(map[string]bool)
+	}
+	for _, k := range keys {
+		p.keys[k] = true
+	}
+}
+
+// RevealPII sets whether the values of fields marked by MarkPII are written
+// as-is, for internal use, rather than masked (the default).
+func (l *Logger) RevealPII(reveal bool) {
+	var v int32
+	if reveal {
+		v = 1
+	}
+	atomic.StoreInt32(&l.shared.pii.reveal, v)
+}
+
+// Returns fields with any PII masked (unless revealed) and marked.
+// fields is not modified.
+func (p *piiState) apply(fields Fields) Fields {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.keys) == 0 {
+		return fields
+	}
+	reveal := atomic.LoadInt32(&p.reveal) != 0
+	var f Fields
+	for k := range fields {
+		if !p.keys[k] {
+			continue
+		}
+		if f == nil {
+			f = fields.copy()
+			f["_has_pii"] = true
+		}
+		if !reveal {
+			f[k] = masked
+		}
+	}
+	if f == nil {
+		return fields
+	}
+	return f
+}
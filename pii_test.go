@@ -0,0 +1,28 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestMarkPII(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestMarkPII")
+	lg.Info = il
+	lg.MarkPII("email", "ssn")
+
+	user := lg.WithFields(Fields{"email": "ann@example.com", "plan": "pro"})
+	user.Infof("Signed up")
+	lg.RevealPII(true)
+	user.Infof("Signed up")
+	lg.Infof("No fields")
+
+	m := regexp.MustCompile(`^I.*pii_test.go:\d+: Signed up _has_pii=true email=\[REDACTED\] plan=pro
+I.*pii_test.go:\d+: Signed up _has_pii=true email=ann@example.com plan=pro
+I.*pii_test.go:\d+: No fields
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
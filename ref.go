@@ -0,0 +1,54 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrorRef returns the correlation ID of the PanicValue in err's wrap chain.
+// This is handy after recovering from Panicf:
+//
+//	if err, ok := recover().(error); ok {
+//	  if ref, ok := log.ErrorRef(err); ok {
+//	    fmt.Fprintf(w, "Internal error, ref %s", ref)
+//	  }
+//	}
+func ErrorRef(err error) (string, bool) {
+	var pv *PanicValue
+	if errors.As(err, &pv) {
+		return pv.Ref, true
+	}
+	return "", false
+}
+
+// The generator set by SetIDGenerator, if any.
+var idGenerator atomic.Value // func() string
+
+// SetIDGenerator sets the function that generates correlation IDs (such as the
+// refs of Panicf and Fatalf), so that they can match the format used elsewhere
+// (e.g. UUIDs or KSUIDs).
+// It must be safe for concurrent use.
+// A nil gen restores the default, 8 random hex characters.
+func SetIDGenerator(gen func() string) {
+	if gen == nil {
+		gen = randomID
+	}
+	idGenerator.Store(gen)
+}
+
+// Returns a new ID from the configured generator.
+func newID() string {
+	if gen, ok := idGenerator.Load().(func() string); ok {
+		return gen()
+	}
+	return randomID()
+}
+
+// Returns a new short, random ID.
+func randomID() string {
+	var b [4]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestPanicfRef(t *testing.T) {
+	el, fl := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestPanicfRef")
+	lg.Error = el
+	lg.Fatal = fl
+	lg.Exit = nil
+
+	var r interface{}
+	func() {
+		defer func() {
+			r = recover()
+		}()
+		lg.Panicf("Test %s", "message")
+	}()
+
+	err, ok := r.(error)
+	if !ok {
+		t.Fatalf("Got panic value %v, want an error", r)
+	}
+	if err.Error() != "Test message" {
+		t.Errorf("Got %q, want %q from the panic error", err.Error(), "Test message")
+	}
+	ref, ok := ErrorRef(fmt.Errorf("wrapped: %w", err))
+	if !ok || len(ref) != 8 {
+		t.Fatalf("Got ref %q (ok %v), want an 8 character ref from the panic error", ref, ok)
+	}
+	m := regexp.MustCompile(`^E.*ref_test.go:\d+: \[ref=` + ref + `\] Test message\n$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+
+	lg.Fatalf("Test %s", "message")
+	m = regexp.MustCompile(`^F.*ref_test.go:\d+: \[ref=[0-9a-f]{8}\] Test message\n$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	n := 0
+	SetIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("id-%d", n)
+	})
+	defer SetIDGenerator(nil)
+
+	fl := new(bytes.Buffer)
+	lg := New("TestSetIDGenerator")
+	lg.Fatal = fl
+	lg.Exit = nil
+	lg.Fatalf("First")
+	lg.Fatalf("Second")
+
+	m := regexp.MustCompile(`^F.*ref_test.go:\d+: \[ref=id-1\] First
+F.*ref_test.go:\d+: \[ref=id-2\] Second
+$`)
+	if s := fl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from fatal log", s, m)
+	}
+
+	SetIDGenerator(nil)
+	if id := newID(); !regexp.MustCompile(`^[0-9a-f]{8}$`).MatchString(id) {
+		t.Errorf("Got %q, want 8 hex characters from the default generator", id)
+	}
+}
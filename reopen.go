@@ -0,0 +1,67 @@
+package log
+
+import (
+	"os"
+	"sync"
+)
+
+// ReopenWriter is an io.Writer that appends to a file by path, and reopens that
+// path whenever the file it has open is no longer the one found there.
+// This is the behavior logrotate expects when it renames the active log and
+// creates a new one (i.e. without copytruncate).
+//
+// The check costs one stat of the path per write.
+// A ReopenWriter is safe for concurrent use.
+type ReopenWriter struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	fi   os.FileInfo
+}
+
+// NewReopenWriter opens (creating if necessary) the file at path for appending.
+func NewReopenWriter(path string) (*ReopenWriter, error) {
+	w := &ReopenWriter{path: path}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Opens w.path, replacing (and closing) any file already open.
+func (w *ReopenWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if w.f != nil {
+		w.f.Close()
+	}
+	w.f, w.fi = f, fi
+	return nil
+}
+
+// Write writes p to the file currently at the writer's path, reopening it first
+// if it has been moved, replaced, or removed.
+func (w *ReopenWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if fi, err := os.Stat(w.path); err != nil || !os.SameFile(fi, w.fi) {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	return w.f.Write(p)
+}
+
+// Close closes the underlying file.
+func (w *ReopenWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
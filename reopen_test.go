@@ -0,0 +1,35 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReopenWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+	w, err := NewReopenWriter(path)
+	if err != nil {
+		t.Fatalf("NewReopenWriter(%v) failed: %v", path, err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// Rotate the file out from under the writer, like logrotate does.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if b, err := os.ReadFile(path + ".1"); err != nil || string(b) != "before\n" {
+		t.Errorf("Got %q (err %v), want %q in the rotated file", b, err, "before\n")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "after\n" {
+		t.Errorf("Got %q (err %v), want %q in the new file", b, err, "after\n")
+	}
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Record is a single message written by a Logger.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Level   Level     `json:"level"`
+	Message string    `json:"msg"`
+}
+
+// ring keeps the most recent records written, up to a fixed number.
+type ring struct {
+	mu   sync.Mutex
+	buf  []Record // Nil when capturing is off.
+	next int      // Where the next record goes.
+	n    int      // How many records are held, ending just before next.
+
+	ttl time.Duration    // From SetRetentionTTL.
+	now func() time.Time // Replaced in tests.
+}
+
+func (r *ring) add(level Level, msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf == nil {
+		return
+	}
+	r.expire()
+	r.buf[r.next] = Record{Time: r.now(), Level: level, Message: msg}
+	r.next = (r.next + 1) % len(r.buf)
+	if r.n < len(r.buf) {
+		r.n++
+	}
+}
+
+// Drops the records older than the TTL, if one is set. r.mu must be held.
+// Records are held in the order they were written, so the expired ones are
+// all at the oldest end.
+func (r *ring) expire() {
+	if r.ttl <= 0 {
+		return
+	}
+	cutoff := r.now().Add(-r.ttl)
+	for r.n > 0 {
+		oldest := (r.next - r.n + len(r.buf)) % len(r.buf)
+		if !r.buf[oldest].Time.Before(cutoff) {
+			return
+		}
+		r.buf[oldest] = Record{}
+		r.n--
+	}
+}
+
+// Returns up to the last n records, oldest first.
+func (r *ring) last(n int) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf == nil {
+		return nil
+	}
+	r.expire()
+	if n < 0 || n > r.n {
+		n = r.n
+	}
+	var all []Record
+	for i := n; i > 0; i-- {
+		all = append(all, r.buf[(r.next-i+len(r.buf))%len(r.buf)])
+	}
+	return all
+}
+
+// CaptureRecent makes the logger keep the last size records it writes in
+// memory, for Recent and RecentJSON.
+// Calling it again starts over with an empty buffer; a size of zero or less
+// stops capturing, which is the default.
+func (l *Logger) CaptureRecent(size int) {
+	r := &l.shared.ring
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf, r.next, r.n = nil, 0, 0
+	if r.now == nil {
+		r.now = time.Now
+	}
+	if size > 0 {
+		r.buf = make([]Record, size)
+	}
+}
+
+// SetRetentionTTL makes the records captured by CaptureRecent expire after d,
+// so that Recent shows only recent activity even when the buffer is not full.
+// Expired records are dropped when records are next written or read, rather
+// than by a background goroutine.
+// Zero or less (the default) keeps records until they are overwritten.
+func (l *Logger) SetRetentionTTL(d time.Duration) {
+	r := &l.shared.ring
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = d
+}
+
+// Recent returns up to the last n captured records, oldest first.
+// A negative n returns all of them.
+// Returns nothing unless CaptureRecent is on.
+func (l *Logger) Recent(n int) []Record {
+	return l.shared.ring.last(n)
+}
+
+// RecentJSON returns up to the last n captured records as a JSON array, oldest
+// first, suitable for serving from a /debug/logs endpoint.
+// See Recent for details.
+func (l *Logger) RecentJSON(n int) []byte {
+	recs := l.Recent(n)
+	if recs == nil {
+		recs = []Record{}
+	}
+	b, err := json.Marshal(recs)
+	if err != nil {
+		// Records hold only strings, times, and levels, so this cannot happen.
+		panic(err)
+	}
+	return b
+}
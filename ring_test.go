@@ -0,0 +1,71 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecentJSON(t *testing.T) {
+	lg := New("TestRecentJSON")
+	lg.Info = new(bytes.Buffer)
+	lg.Warn = new(bytes.Buffer)
+
+	lg.Infof("Not captured")
+	if got := string(lg.RecentJSON(3)); got != "[]" {
+		t.Errorf("Got %v, want an empty array before CaptureRecent", got)
+	}
+
+	lg.CaptureRecent(4)
+	for i := 1; i <= 5; i++ {
+		lg.Infof("Message %d", i)
+	}
+	lg.Warnf("Message %d", 6)
+
+	var got []Record
+	if err := json.Unmarshal(lg.RecentJSON(3), &got); err != nil {
+		t.Fatalf("RecentJSON returned invalid JSON: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Got %v records, want 3", len(got))
+	}
+	for i, r := range got {
+		if want := fmt.Sprintf("Message %d", i+4); r.Message != want {
+			t.Errorf("Got message %q, want %q", r.Message, want)
+		}
+	}
+	if got[2].Level != LevelWarn {
+		t.Errorf("Got level %v, want %v for the last record", got[2].Level, LevelWarn)
+	}
+
+	if n := len(lg.Recent(-1)); n != 4 {
+		t.Errorf("Got %v records, want all 4 that fit in the ring", n)
+	}
+}
+
+func TestRetentionTTL(t *testing.T) {
+	lg := New("TestRetentionTTL")
+	lg.Info = new(bytes.Buffer)
+	now := time.Unix(1000, 0)
+	lg.shared.ring.now = func() time.Time { return now }
+	lg.CaptureRecent(10)
+	lg.SetRetentionTTL(time.Minute)
+
+	lg.Infof("Message 1")
+	now = now.Add(30 * time.Second)
+	lg.Infof("Message 2")
+	now = now.Add(45 * time.Second)
+
+	got := lg.Recent(-1)
+	if len(got) != 1 || got[0].Message != "Message 2" {
+		t.Errorf("Got %v, want only Message 2 once Message 1 expired", got)
+	}
+
+	lg.Infof("Message 3")
+	now = now.Add(time.Hour)
+	if got := lg.Recent(-1); len(got) != 0 {
+		t.Errorf("Got %v, want no records once all expired", got)
+	}
+}
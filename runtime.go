@@ -0,0 +1,56 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// StartRuntimeStats logs heap, goroutine, and GC statistics every d, at V level
+// level, until the returned stop function is called.
+// Nothing is logged (or even measured) while the logger is not loud enough, so
+// it can be left running and turned on by raising the verbosity.
+func (l *Logger) StartRuntimeStats(d time.Duration, level int) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		t := time.NewTicker(d)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				if l.LoudEnough(level) {
+					l.logRuntimeStats()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func (l *Logger) logRuntimeStats() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	var lastPause time.Duration
+	if m.NumGC > 0 {
+		lastPause = time.Duration(m.PauseNs[(m.NumGC+255)%256])
+	}
+	l.writeFields(LevelInfo, l.i, l.calldepth-1, Fields{
+		"heap_alloc":     m.HeapAlloc,
+		"goroutines":     runtime.NumGoroutine(),
+		"num_gc":         m.NumGC,
+		"gc_pause_total": time.Duration(m.PauseTotalNs),
+		"gc_pause_last":  lastPause,
+	}, "Runtime stats:")
+}
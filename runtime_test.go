@@ -0,0 +1,58 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a strings.Builder that is safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestStartRuntimeStats(t *testing.T) {
+	il := new(syncBuffer)
+	lg := New("TestStartRuntimeStats")
+	lg.Info = il
+	lg.SetVerbosity(1)
+
+	stop := lg.StartRuntimeStats(time.Millisecond, 2)
+	time.Sleep(10 * time.Millisecond)
+	if s := il.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from info log below the verbosity gate", s)
+	}
+
+	lg.BoostVerbosity(2, time.Minute)
+	deadline := time.Now().Add(time.Second)
+	for il.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	stop()
+
+	m := regexp.MustCompile(`^I.*: Runtime stats: gc_pause_last=\S+ gc_pause_total=\S+ goroutines=\d+ heap_alloc=\d+ num_gc=\d+\n`)
+	s := il.String()
+	if !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if s2 := il.String(); s2 != s {
+		t.Errorf("Got more stats after stop: %v", strings.TrimPrefix(s2, s))
+	}
+}
@@ -0,0 +1,159 @@
+package log
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// Per-level sample rates and counters; see SetSampleRateFor.
+// Accessed atomically.
+type sampleState struct {
+	rates, counts [numLevels]int64
+}
+
+// SetSampleRateFor makes the logger write only one in every n messages at the
+// given level (the first, then the n+1th, and so on), so that e.g. DEBUG can
+// be sampled harder than INFO.
+// Only DEBUG and INFO may be sampled; WARN and above are always written.
+// Messages logged with a context marked by WithForceSample are never sampled
+// out, and SetSampleContext can keep those dropped to write before an error.
+// An n of 1 or less turns sampling off for the level, which is the default.
+func (l *Logger) SetSampleRateFor(level Level, n int) {
+	if level != LevelDebug && level != LevelInfo {
+		return
+	}
+	s := &l.shared.sample
+	atomic.StoreInt64(&s.rates[level], int64(n))
+	atomic.StoreInt64(&s.counts[level], 0)
+}
+
+// Returns whether the next message at level is sampled in, and so should be
+// written.
+func (s *sampleState) keep(level Level) bool {
+	n := atomic.LoadInt64(&s.rates[level])
+	if n <= 1 {
+		return true
+	}
+	return (atomic.AddInt64(&s.counts[level], 1)-1)%n == 0
+}
+
+// Messages dropped by sample rates, kept for SetSampleContext.
+type sampledOut struct {
+	mu   sync.Mutex
+	max  int
+	recs []sampledRecord
+}
+
+// A message dropped by sample rates, as it would have been written.
+type sampledRecord struct {
+	l     *Logger
+	level Level
+	lg    Logable
+	text  string
+	e     Entry
+}
+
+// SetSampleContext makes the logger keep the last n messages dropped by
+// SetSampleRateFor, and write them ahead of the next ERROR or FATAL message,
+// so that the error comes with the context that led up to it.
+// They keep their own level, time, and caller.
+// An n of zero or less turns this off (and forgets any kept messages), which is
+// the default.
+func (l *Logger) SetSampleContext(n int) {
+	s := &l.shared.sampledOut
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	s.max, s.recs = n, nil
+}
+
+// Keeps a dropped message from l, if SetSampleContext asked for them, in place
+// of the oldest kept if there are already enough.
+// depth is as for Logger.output, called from this function.
+func (s *sampledOut) add(l *Logger, level Level, lg Logable, depth int, text string, e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.max == 0 {
+		return
+	}
+	if std, ok := lg.(*log.Logger); ok {
+		stamp(std, depth, &e)
+	}
+	if len(s.recs) == s.max {
+		copy(s.recs, s.recs[1:])
+		s.recs = s.recs[:len(s.recs)-1]
+	}
+	s.recs = append(s.recs, sampledRecord{l, level, lg, text, e})
+}
+
+// Writes the kept messages, oldest first, and forgets them.
+// depth is as for Logger.output, called from this function.
+func (s *sampledOut) flush(depth int) {
+	s.mu.Lock()
+	recs := s.recs
+	s.recs = nil
+	s.mu.Unlock()
+	for _, r := range recs {
+		r.l.emit(r.level, r.lg, depth+1, r.text, r.e)
+	}
+}
+
+// The context key type for WithForceSample.
+type forceSampleKey struct{}
+
+// WithForceSample returns a copy of ctx marking everything logged with it as
+// sampled, so that InfofCtx bypasses the logger's Limiter and sample rates.
+// Use it for requests whose trace was sampled, so the trace has complete logs
+// even while the rest are throttled.
+func WithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+// Returns whether ctx was marked by WithForceSample.
+func forceSampled(ctx context.Context) bool {
+	v, _ := ctx.Value(forceSampleKey{}).(bool)
+	return v
+}
+
+// InfofCtx writes log messages at INFO level, like Infof, except that if ctx
+// was marked by WithForceSample the message is never dropped by the Limiter
+// or sampled out.
+func (l *Logger) InfofCtx(ctx context.Context, format string, v ...interface{}) {
+	l.forCtx(ctx).write(LevelInfo, l.i, l.calldepth, format, v...)
+}
+
+// InfofCtx writes log messages at INFO level to the root logger.
+// See Logger.InfofCtx for details.
+func InfofCtx(ctx context.Context, format string, v ...interface{}) {
+	Root.forCtx(ctx).write(LevelInfo, Root.i, Root.calldepth, format, v...)
+}
+
+// SetContextExtractor makes the ...Ctx methods (like InfofCtx) attach the
+// fields that extract pulls from their context, e.g. a request or trace ID.
+// This keeps the package agnostic about the application's context keys.
+// extract must be safe for concurrent use, and may return nil.
+// A nil extract attaches nothing, which is the default.
+func (l *Logger) SetContextExtractor(extract func(ctx context.Context) Fields) {
+	l.shared.extractor.Store(extract)
+}
+
+// Returns the logger to write with for ctx.
+func (l *Logger) forCtx(ctx context.Context) *Logger {
+	c := l
+	if extract, _ := l.shared.extractor.Load().(func(context.Context) Fields); extract != nil {
+		if f := extract(ctx); len(f) > 0 {
+			c = l.WithFields(f)
+		}
+	}
+	if forceSampled(ctx) {
+		if c == l {
+			c = l.derive()
+		}
+		c.unlimited = true
+	}
+	return c
+}
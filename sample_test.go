@@ -0,0 +1,141 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInfofCtx(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestInfofCtx")
+	lg.Info = il
+	lg.SetLimiter(fakeLimiter(false))
+
+	lg.InfofCtx(context.Background(), "Dropped")
+	lg.InfofCtx(WithForceSample(context.Background()), "Test %s", "message")
+
+	m := regexp.MustCompile(`^I.*sample_test.go:\d+: Test message\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	if got := lg.Dropped()[LevelInfo]; got != 1 {
+		t.Errorf("Got %v dropped info records, want 1", got)
+	}
+}
+
+type requestIDKey struct{}
+
+func TestSetContextExtractor(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetContextExtractor")
+	lg.Info = il
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r-42")
+	lg.InfofCtx(ctx, "Before")
+	lg.SetContextExtractor(func(ctx context.Context) Fields {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return Fields{"request_id": id}
+		}
+		return nil
+	})
+	lg.InfofCtx(ctx, "Test %s", "message")
+	lg.InfofCtx(context.Background(), "Without")
+
+	m := regexp.MustCompile(`^I.*sample_test.go:\d+: Before
+I.*sample_test.go:\d+: Test message request_id=r-42
+I.*sample_test.go:\d+: Without
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+func TestSetSampleRateFor(t *testing.T) {
+	dl, il, wl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetSampleRateFor")
+	lg.Debug, lg.Info, lg.Warn = dl, il, wl
+
+	lg.SetSampleRateFor(LevelDebug, 10)
+	lg.SetSampleRateFor(LevelInfo, 2)
+	lg.SetSampleRateFor(LevelWarn, 5)
+	for i := 0; i < 20; i++ {
+		lg.Debugf("Test message")
+		lg.Infof("Test message")
+		lg.Warnf("Test message")
+	}
+	lg.InfofCtx(WithForceSample(context.Background()), "Forced")
+
+	if got := strings.Count(dl.String(), "\n"); got != 2 {
+		t.Errorf("Got %v debug records, want 2 of 20 at a rate of 10", got)
+	}
+	if got := strings.Count(il.String(), "Test message\n"); got != 10 {
+		t.Errorf("Got %v info records, want 10 of 20 at a rate of 2", got)
+	}
+	if got := strings.Count(wl.String(), "\n"); got != 20 {
+		t.Errorf("Got %v warn records, want all 20, as WARN is never sampled", got)
+	}
+	if s := il.String(); !strings.HasSuffix(s, "Forced\n") {
+		t.Errorf("Got %v, want a forced record last from info log", s)
+	}
+
+	il.Reset()
+	lg.SetSampleRateFor(LevelInfo, 0)
+	lg.Infof("Test message")
+	lg.Infof("Test message")
+	if got := strings.Count(il.String(), "\n"); got != 2 {
+		t.Errorf("Got %v info records, want 2 with sampling off", got)
+	}
+}
+
+func TestSetSampleContext(t *testing.T) {
+	il, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetSampleContext")
+	lg.Info, lg.Error = il, el
+	lg.SetSampleRateFor(LevelInfo, 100)
+	lg.SetSampleContext(2)
+
+	lg.Infof("Step 1")
+	lg.Infof("Step 2")
+	lg.Infof("Step 3")
+	lg.Infof("Step 4")
+	lg.Errorf("Test message")
+	lg.Errorf("Test message")
+
+	m := regexp.MustCompile(`^I.*sample_test.go:\d+: Step 1
+I.*sample_test.go:\d+: Step 3
+I.*sample_test.go:\d+: Step 4
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	if s := el.String(); !regexp.MustCompile(`^(E.*sample_test.go:\d+: Test message\n){2}$`).MatchString(s) {
+		t.Errorf("Got %v, want two errors from error log", s)
+	}
+}
+
+func TestSetSampleContextOrder(t *testing.T) {
+	w := new(bytes.Buffer)
+	lg := New("TestSetSampleContextOrder")
+	lg.Info, lg.Error = w, w
+	lg.SetSampleRateFor(LevelInfo, 100)
+	lg.SetSampleContext(5)
+
+	lg.Infof("Connecting")
+	lg.Infof("Retrying")
+	lg.Errorf("Test message")
+
+	m := regexp.MustCompile(`^I.*sample_test.go:\d+: Connecting
+I.*sample_test.go:(\d+): Retrying
+E.*sample_test.go:(\d+): Test message
+$`)
+	sm := m.FindStringSubmatch(w.String())
+	if sm == nil {
+		t.Fatalf("Got %v, want something matching %v", w, m)
+	}
+	if sm[1] == sm[2] {
+		t.Errorf("Got %v, want the dropped message's own line, not the error's", w)
+	}
+}
@@ -0,0 +1,9 @@
+package log
+
+// SetSchemaVersion sets the version of the log schema recorded in every entry,
+// so that consumers of structured records (see JSONFormatter) can tell formats
+// apart as they evolve.
+// An empty version (the default) leaves it out.
+func (l *Logger) SetSchemaVersion(version string) {
+	l.shared.schema.Store(version)
+}
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSetSchemaVersion(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetSchemaVersion")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.Infof("Test message")
+	if s := il.String(); strings.Contains(s, `"schema"`) {
+		t.Errorf("Got %v, want no schema from info log before SetSchemaVersion", s)
+	}
+
+	il.Reset()
+	lg.SetSchemaVersion("2")
+	lg.Infof("Test message")
+	m := regexp.MustCompile(`^\{"level":"info",.*"msg":"Test message","schema":"2"\}\n$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+)
+
+// What masked text is replaced with.
+const masked = "[REDACTED]"
+
+var (
+	// AWS access key IDs.
+	awsKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+
+	// JSON Web Tokens: three base64url segments, the first an encoded `{"`.
+	jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+	// Secret-sounding keys with a value; only the value is masked.
+	secretKVPattern = regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api_?key)([=:]\s*)("[^"]*"|\S+)`)
+
+	// Runs of 13 to 19 digits, optionally grouped by spaces or dashes; only
+	// those passing the Luhn check are masked.
+	cardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// Returns msg and fields with anything that looks like a secret masked, adding
+// a _redacted=true field if anything was.
+// fields is not modified.
+func maskSecrets(msg string, fields Fields) (string, Fields) {
+	out := maskText(msg)
+	var f Fields
+	for k, v := range fields {
+		s := formatValue(v)
+		if m := maskText(s); m != s {
+			if f == nil {
+				f = fields.copy()
+			}
+			f[k] = m
+		}
+	}
+	if out == msg && f == nil {
+		return msg, fields
+	}
+	if f == nil {
+		f = fields.copy()
+	}
+	f["_redacted"] = true
+	return out, f
+}
+
+// Returns s with anything that looks like a secret masked.
+func maskText(s string) string {
+	out := awsKeyPattern.ReplaceAllString(s, masked)
+	out = jwtPattern.ReplaceAllString(out, masked)
+	out = secretKVPattern.ReplaceAllString(out, "${1}${2}"+masked)
+	out = cardPattern.ReplaceAllStringFunc(out, func(s string) string {
+		if luhn(s) {
+			return masked
+		}
+		return s
+	})
+	return out
+}
+
+// Returns whether the digits of s pass the Luhn checksum used by card numbers.
+func luhn(s string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	sum := 0
+	for i := range digits {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,37 @@
+package log
+
+import "errors"
+
+// severer is implemented by errors that know how severe they are.
+type severer interface {
+	Severity() Level
+}
+
+// LogError writes err's message at the level reported by its Severity() Level
+// method, found anywhere in its wrap chain, or at ERROR level if there is none.
+// This lets the code producing an error decide how loudly it is logged.
+// A FATAL severity is written to the Fatal writer, but Exit is not called; the
+// caller still has the error to act on.
+func (l *Logger) LogError(err error) {
+	logError(l, err)
+}
+
+// LogError writes err's message to the root logger at the level reported by its
+// Severity() Level method.
+// See Logger.LogError for details.
+func LogError(err error) {
+	logError(Root, err)
+}
+
+// Shared by both forms of LogError, so the call depth is the same for each.
+func logError(l *Logger, err error) {
+	level := LevelError
+	var s severer
+	if errors.As(err, &s) {
+		level = s.Severity()
+	}
+	if level < LevelDebug || level > LevelFatal {
+		level = LevelError
+	}
+	l.write(level, l.logable(level), l.calldepth+1, "%v", err)
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type warnError struct{}
+
+func (warnError) Error() string   { return "Test message" }
+func (warnError) Severity() Level { return LevelWarn }
+
+func TestLogError(t *testing.T) {
+	wl, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLogError")
+	lg.Warn = wl
+	lg.Error = el
+
+	lg.LogError(warnError{})
+	if s := wl.String(); !wmatcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, wmatcher)
+	}
+	if s := el.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from error log", s)
+	}
+
+	wl.Truncate(0)
+	lg.LogError(fmt.Errorf("wrapped: %w", warnError{}))
+	if s := wl.String(); len(s) == 0 {
+		t.Errorf("Got empty, want a wrapped warning in warn log")
+	}
+
+	lg.LogError(errors.New("Test message"))
+	if s := el.String(); !ematcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, ematcher)
+	}
+}
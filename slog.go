@@ -0,0 +1,94 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler returns a slog.Handler that writes through l, so
+// slog.New(NewSlogHandler(Root)) sends slog records to this package's writers.
+//
+// Records at slog.LevelError and above go to l's ERROR writer, at
+// slog.LevelWarn and above to its WARN writer, and the rest to its INFO writer.
+// Records below slog.LevelInfo are treated like V messages: slog.LevelDebug
+// needs a verbosity of 1, each further 4 below it one more.
+// Attributes are appended as key=value fields, with group names joined to
+// their keys by dots (like "req.id=7").
+//
+// Source locations are those of the caller of the slog.Logger, so they are
+// wrong if the handler is wrapped by another.
+func NewSlogHandler(l *Logger) slog.Handler {
+	return &slogHandler{l: l}
+}
+
+type slogHandler struct {
+	l      *Logger
+	fields Fields // From WithAttrs.
+	prefix string // The open groups, each followed by a dot.
+}
+
+// Returns the verbosity needed to log at level, which is below slog.LevelInfo.
+func slogVerbosity(level slog.Level) int {
+	return int(slog.LevelInfo-level+3) / 4
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo || h.l.LoudEnough(slogVerbosity(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	level, lg := LevelInfo, h.l.i
+	switch {
+	case r.Level >= slog.LevelError:
+		level, lg = LevelError, h.l.e
+	case r.Level >= slog.LevelWarn:
+		level, lg = LevelWarn, h.l.w
+	case r.Level < slog.LevelInfo && !h.l.LoudEnough(slogVerbosity(r.Level)):
+		return nil
+	}
+	f := h.fields.copy()
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(f, h.prefix, a)
+		return true
+	})
+	h.l.writeFields(level, lg, h.l.calldepth+2, f, "%s", r.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.fields = h.fields.copy()
+	for _, a := range attrs {
+		addAttr(c.fields, h.prefix, a)
+	}
+	return &c
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix += name + "."
+	return &c
+}
+
+// Adds a to f, its key prefixed by prefix, flattening groups into dotted keys.
+// Follows slog's rules: attributes with empty keys are dropped, as are empty
+// groups, and the attributes of a group with an empty key are inlined.
+func addAttr(f Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			addAttr(f, prefix, g)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	f[prefix+a.Key] = a.Value.Any()
+}
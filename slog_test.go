@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	il, wl, el := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSlogHandler")
+	lg.Info, lg.Warn, lg.Error = il, wl, el
+	lg.SetVerbosity(0)
+
+	sl := slog.New(NewSlogHandler(lg)).With("app", "test").WithGroup("req")
+	sl.Info("Handled", "id", 7, slog.Group("user", "name", "ann"))
+	sl.Warn("Slow", "ms", 900)
+	sl.Error("Failed", slog.Group("", "inline", true), slog.Group("empty"))
+	sl.Debug("Hidden")
+	lg.SetVerbosity(1)
+	sl.Debug("Shown")
+	sl.Log(nil, slog.LevelDebug-4, "Hidden")
+
+	m := regexp.MustCompile(`^I.*slog_test.go:\d+: Handled app=test req.id=7 req.user.name=ann
+I.*slog_test.go:\d+: Shown app=test
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	m = regexp.MustCompile(`^W.*slog_test.go:\d+: Slow app=test req.ms=900
+$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+	m = regexp.MustCompile(`^E.*slog_test.go:\d+: Failed app=test req.inline=true
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// The context key type for Span.
+type spanKey struct{}
+
+// Span logs the start of the named operation at INFO level, and returns a child
+// of ctx carrying a logger (see FromContext) that tags every message with the
+// span's fields, and a function that logs the end of the span with its
+// duration:
+//
+//	ctx, end := lg.Span(ctx, "checkout")
+//	defer end()
+//	log.FromContext(ctx).Infof("Charging card")
+//
+// The fields are span (the name), span_id (a new correlation ID), and, for a
+// span started with the context of another, parent_span_id.
+// Only the first call of the returned function logs.
+// This gives lightweight tracing without a tracing system.
+func (l *Logger) Span(ctx context.Context, name string) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id := newID()
+	f := Fields{"span": name, "span_id": id}
+	if parent, ok := ctx.Value(spanKey{}).(string); ok {
+		f["parent_span_id"] = parent
+	}
+	c := l.WithFields(f)
+	c.write(LevelInfo, c.i, c.calldepth, "Span %s started", name)
+
+	start := time.Now()
+	var ended int32
+	end := func() {
+		if atomic.CompareAndSwapInt32(&ended, 0, 1) {
+			c.writeFields(LevelInfo, c.i, c.calldepth, Fields{"duration": time.Since(start)}, "Span %s ended", name)
+		}
+	}
+	return NewContext(context.WithValue(ctx, spanKey{}, id), c), end
+}
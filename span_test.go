@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestSpan(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSpan")
+	lg.Info = il
+
+	ctx, end := lg.Span(context.Background(), "checkout")
+	FromContext(ctx).Infof("Test message")
+	_, endChild := lg.Span(ctx, "charge")
+	endChild()
+	end()
+	end()
+
+	m := regexp.MustCompile(`^I.*span_test.go:\d+: Span checkout started span=checkout span_id=(\w+)
+I.*span_test.go:\d+: Test message span=checkout span_id=(\w+)
+I.*span_test.go:\d+: Span charge started parent_span_id=(\w+) span=charge span_id=(\w+)
+I.*span_test.go:\d+: Span charge ended duration=\S+ parent_span_id=(\w+) span=charge span_id=(\w+)
+I.*span_test.go:\d+: Span checkout ended duration=\S+ span=checkout span_id=(\w+)
+$`)
+	sm := m.FindStringSubmatch(il.String())
+	if sm == nil {
+		t.Fatalf("Got %v, want something matching %v from info log", il, m)
+	}
+	parent, child := sm[1], sm[4]
+	for _, i := range []int{2, 3, 5, 7} {
+		if sm[i] != parent {
+			t.Errorf("Got span ID %v, want the parent's %v", sm[i], parent)
+		}
+	}
+	if sm[6] != child || child == parent {
+		t.Errorf("Got child span IDs %v and %v, want the same, different from the parent's %v", child, sm[6], parent)
+	}
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Returns the stack of the calling goroutine for IncludeStack, starting skip
+// frames above the caller of stack, as indented lines beneath a message:
+//
+//	main.handle(...)
+//	    /src/main.go:42
+//
+// Returns "" if IncludeStack is off, and at most StackDepth frames if set.
+func (l *Logger) stack(skip int) string {
+	if !l.IncludeStack {
+		return ""
+	}
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(skip+2, pcs)]
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for n := 0; l.StackDepth <= 0 || n < l.StackDepth; n++ {
+		f, more := frames.Next()
+		if f.Function == "runtime.goexit" {
+			break
+		}
+		b.WriteString("\n    " + f.Function + "(...)\n        " + f.File + ":" + strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// Returns a logger that writes the stack for IncludeStack (as from stack(skip),
+// called by this function's caller) after the next message and its fields, or
+// l itself if IncludeStack is off.
+func (l *Logger) withStack(skip int) *Logger {
+	s := l.stack(skip + 1)
+	if s == "" {
+		return l
+	}
+	c := l.derive()
+	c.trace = s
+	return c
+}
+
+// DumpGoroutines writes the stacks of all goroutines (as a SIGQUIT would print
+// them) at INFO level, but only if the configured verbosity is equal or greater
+// than the provided level.
+// This helps find out what a hung process is doing, without killing it.
+func (l *Logger) DumpGoroutines(level int) {
+	dumpGoroutines(l, level)
+}
+
+// DumpGoroutines writes the stacks of all goroutines at INFO level to the root
+// logger, but only if its verbosity is equal or greater than the provided level.
+func DumpGoroutines(level int) {
+	dumpGoroutines(Root, level)
+}
+
+// Shared by both forms of DumpGoroutines, so the call depth is the same for
+// each.
+func dumpGoroutines(l *Logger, level int) {
+	if !l.LoudEnough(level) {
+		return
+	}
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	l.write(LevelInfo, l.i, l.calldepth+1, "Goroutine dump:\n%s", strings.TrimSuffix(string(buf), "\n"))
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestIncludeStack(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestIncludeStack")
+	lg.Error = el
+
+	lg.Errorf("Test message")
+	if s := el.String(); !ematcher.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log without a stack", s, ematcher)
+	}
+
+	lg.IncludeStack = true
+	el.Reset()
+	lg.Errorf("Test message")
+	m := regexp.MustCompile(`^E.*stack_test.go:\d+: Test message
+    .*\.TestIncludeStack\(\.\.\.\)
+        .*/stack_test.go:\d+
+    testing\.tRunner\(\.\.\.\)
+`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+
+	el.Reset()
+	func() {
+		defer func() { recover() }()
+		lg.Panicf("Test message")
+	}()
+	m = regexp.MustCompile(`^E.*stack_test.go:\d+: \[ref=\w+\] Test message
+    .*\.TestIncludeStack\.func1\(\.\.\.\)
+        .*/stack_test.go:\d+
+`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestStackDepth")
+	lg.Error = el
+	lg.IncludeStack = true
+	lg.StackDepth = 2
+
+	lg.Errorf("Test message")
+	m := regexp.MustCompile(`^E.*stack_test.go:\d+: Test message
+    .*\.TestStackDepth\(\.\.\.\)
+        .*/stack_test.go:\d+
+    testing\.tRunner\(\.\.\.\)
+        .*/testing.go:\d+
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
+
+func TestIncludeStackFields(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestIncludeStackFields")
+	lg.Error = el
+	lg.IncludeStack = true
+
+	lg.WithFields(Fields{"user": "ann"}).Errorf("Test message")
+	m := regexp.MustCompile(`^E.*stack_test.go:\d+: Test message user=ann
+    .*\.TestIncludeStackFields\(\.\.\.\)
+        .*/stack_test.go:\d+
+`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+
+	el.Reset()
+	lg.SetFormatter(JSONFormatter{})
+	lg.WithFields(Fields{"user": "ann"}).Errorf("Test message")
+	var got struct {
+		Msg    string
+		Fields Fields
+		Stack  string
+	}
+	if err := json.Unmarshal(el.Bytes(), &got); err != nil {
+		t.Fatalf("Got %v, want JSON from error log: %v", el.String(), err)
+	}
+	if got.Msg != "Test message" || got.Fields["user"] != "ann" || !strings.Contains(got.Stack, ".TestIncludeStackFields(...)") {
+		t.Errorf("Got %+v, want the message, field, and stack apart", got)
+	}
+}
+
+func TestDumpGoroutines(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestDumpGoroutines")
+	lg.Info = il
+	lg.SetVerbosity(1)
+
+	lg.DumpGoroutines(2)
+	if il.Len() != 0 {
+		t.Errorf("Got %v, want nothing from info log below the verbosity", il)
+	}
+
+	lg.DumpGoroutines(1)
+	m := regexp.MustCompile(`^I.*stack_test.go:\d+: Goroutine dump:
+goroutine \d+ \[running\]:
+(?s:.*)\.TestDumpGoroutines\(`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,24 @@
+package log
+
+// Sub returns a derived logger for a subsystem, whose messages are prefixed
+// with its name, like "[db] Connected".
+// Calling Sub on the result nests the names with dots, so
+// Root.Sub("db").Sub("pool") prefixes "[db.pool]".
+// The name is also appended to the logger's Name, for its error descriptions.
+//
+// The derived logger shares this logger's writers, verbosity, and state; set
+// writers on the original.
+func (l *Logger) Sub(name string) *Logger {
+	c := l.derive()
+	c.sub = joinName(l.sub, name)
+	c.name = joinName(l.name, name)
+	return c
+}
+
+// Joins the names with a dot, unless parent is empty.
+func joinName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestSub(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSub")
+	lg.Info = il
+
+	db := lg.Sub("db")
+	pool := db.Sub("pool")
+	lg.Infof("Test message")
+	db.Infof("Test message")
+	pool.WithFields(Fields{"conns": 4}).Infof("Test message")
+
+	m := regexp.MustCompile(`^I.*sub_test.go:\d+: Test message
+I.*sub_test.go:\d+: \[db\] Test message
+I.*sub_test.go:\d+: \[db.pool\] Test message conns=4
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	if got, want := pool.Name(), "TestSub.db.pool"; got != want {
+		t.Errorf("Got name %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Counts returns how many records the logger has written at each level.
+func (l *Logger) Counts() map[Level]int {
+	c := make(map[Level]int, numLevels)
+	for lv := Level(0); lv < numLevels; lv++ {
+		c[lv] = int(atomic.LoadInt64(&l.shared.counts[lv]))
+	}
+	return c
+}
+
+// Dropped returns how many records the logger's Limiter has dropped at each
+// level.
+func (l *Logger) Dropped() map[Level]int {
+	c := make(map[Level]int, numLevels)
+	for lv := Level(0); lv < numLevels; lv++ {
+		c[lv] = int(atomic.LoadInt64(&l.shared.dropped[lv]))
+	}
+	return c
+}
+
+// LogSummary writes an INFO line summarizing how many records the logger has
+// written at each level, and how many its Limiter dropped.
+// Call it (or defer it) at normal shutdown for a quick post-mortem of logging
+// activity.
+func (l *Logger) LogSummary() {
+	var b strings.Builder
+	b.WriteString("Log summary:")
+	for lv := Level(0); lv < numLevels; lv++ {
+		b.WriteString(" " + lv.String() + "=")
+		b.WriteString(strconv.FormatInt(atomic.LoadInt64(&l.shared.counts[lv]), 10))
+	}
+	var dropped int64
+	for lv := Level(0); lv < numLevels; lv++ {
+		dropped += atomic.LoadInt64(&l.shared.dropped[lv])
+	}
+	b.WriteString(" dropped=" + strconv.FormatInt(dropped, 10))
+	l.write(LevelInfo, l.i, l.calldepth, "%s", b.String())
+}
+
+// LogSummaryOnExit arranges for LogSummary to run when a Fatalf exits (by Exit,
+// or FatalfRequiresExit), before the writers are flushed so that the summary
+// is not left in a buffer.
+// For a normal shutdown, also `defer l.LogSummary()` in main.
+func (l *Logger) LogSummaryOnExit() {
+	atomic.StoreInt32(&l.shared.summaryOnExit, 1)
+}
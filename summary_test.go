@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestLogSummaryOnExit(t *testing.T) {
+	il, wl, el, fl := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLogSummaryOnExit")
+	lg.Info = il
+	lg.Warn = wl
+	lg.Error = el
+	lg.Fatal = fl
+	called := false
+	lg.Exit = func() {
+		called = true
+	}
+
+	lg.LogSummaryOnExit()
+	lg.Infof("One")
+	lg.Infof("Two")
+	lg.Warnf("Three")
+	lg.Fatalf("Four")
+
+	if !called {
+		t.Errorf("The original Exit function was not called")
+	}
+	m := regexp.MustCompile("I.*: Log summary: debug=0 info=2 warn=1 error=0 fatal=1 audit=0 dropped=0\n$")
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+	if got := lg.Counts()[LevelInfo]; got != 3 {
+		t.Errorf("Got %v info records, want 3 including the summary", got)
+	}
+}
+
+func TestLogSummaryOnExitBuffered(t *testing.T) {
+	il := new(bytes.Buffer)
+	bw := bufio.NewWriter(il)
+	lg := New("TestLogSummaryOnExitBuffered")
+	lg.Info = bw
+	lg.Fatal = new(bytes.Buffer)
+	var atExit string
+	lg.LogSummaryOnExit()
+	lg.Exit = func() {
+		atExit = il.String()
+	}
+
+	lg.Infof("One")
+	lg.Fatalf("Two")
+
+	m := regexp.MustCompile("I.*: One\nI.*: Log summary: .* info=1 .*\n$")
+	if !m.MatchString(atExit) {
+		t.Errorf("Got %v, want something matching %v from info log by Exit", atExit, m)
+	}
+}
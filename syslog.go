@@ -0,0 +1,59 @@
+//go:build !windows && !plan9
+
+package log
+
+import "log/syslog"
+
+// SyslogWriter is an io.Writer that sends each record written to it to syslog,
+// at the severity matching its level: LOG_INFO for INFO, LOG_WARNING for WARN,
+// LOG_ERR for ERROR, LOG_CRIT for FATAL, LOG_DEBUG for DEBUG, and LOG_NOTICE
+// for the audit trail.
+// It is an EntryWriter, so one SyslogWriter can be assigned to several levels.
+// Each record is sent as its caller, message, and fields; syslog adds its own
+// timestamp.
+// Text written to it by other means is sent with the writer's default
+// severity.
+type SyslogWriter struct {
+	w *syslog.Writer
+}
+
+// NewSyslogWriter returns a SyslogWriter connected to the local syslog daemon,
+// logging with the facility and default severity of priority, and with tag.
+func NewSyslogWriter(priority syslog.Priority, tag string) (*SyslogWriter, error) {
+	w, err := syslog.New(priority, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogWriter{w}, nil
+}
+
+// WriteEntry sends e to syslog as one message, at the severity of its level.
+func (s *SyslogWriter) WriteEntry(e Entry) error {
+	msg := e.text()
+	switch e.Level {
+	case LevelDebug:
+		return s.w.Debug(msg)
+	case LevelInfo:
+		return s.w.Info(msg)
+	case LevelWarn:
+		return s.w.Warning(msg)
+	case LevelError:
+		return s.w.Err(msg)
+	case LevelFatal:
+		return s.w.Crit(msg)
+	case levelAudit:
+		return s.w.Notice(msg)
+	}
+	_, err := s.w.Write([]byte(msg))
+	return err
+}
+
+// Write sends p to syslog as one message, at the default severity.
+func (s *SyslogWriter) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// Close closes the connection to syslog.
+func (s *SyslogWriter) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,56 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"log/syslog"
+	"net"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSyslogWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram(%v) failed: %v", path, err)
+	}
+	defer conn.Close()
+	sw, err := syslog.Dial("unixgram", path, syslog.LOG_DAEMON|syslog.LOG_INFO, "test")
+	if err != nil {
+		t.Fatalf("syslog.Dial(%v) failed: %v", path, err)
+	}
+	w := &SyslogWriter{sw}
+	defer w.Close()
+
+	lg := New("TestSyslogWriter")
+	lg.Info, lg.Warn, lg.Error, lg.Fatal = w, w, w, w
+	lg.Exit = nil
+
+	lg.Infof("Test message")
+	lg.Warnf("Test message")
+	lg.Errorf("Test message")
+	lg.Fatalf("Test message")
+	lg.SetFormatter(JSONFormatter{})
+	lg.WithFields(Fields{"user": "ann"}).Warnf("Test message")
+
+	buf := make([]byte, 1024)
+	// LOG_DAEMON is 3<<3; the severities are 6, 4, 3, 2, and 4 (even with a
+	// Formatter).
+	for _, m := range []*regexp.Regexp{
+		regexp.MustCompile(`^<30>.* test\[\d+\]: syslog_test.go:\d+: Test message\n$`),
+		regexp.MustCompile(`^<28>.* test\[\d+\]: syslog_test.go:\d+: Test message\n$`),
+		regexp.MustCompile(`^<27>.* test\[\d+\]: syslog_test.go:\d+: Test message\n$`),
+		regexp.MustCompile(`^<26>.* test\[\d+\]: syslog_test.go:\d+: \[ref=\w+\] Test message\n$`),
+		regexp.MustCompile(`^<28>.* test\[\d+\]: syslog_test.go:\d+: Test message user=ann\n$`),
+	} {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if s := string(buf[:n]); !m.MatchString(s) {
+			t.Errorf("Got %q, want something matching %v from syslog", s, m)
+		}
+	}
+}
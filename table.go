@@ -0,0 +1,63 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"text/tabwriter"
+)
+
+// InfofTable writes headers and rows as an aligned text table at INFO level,
+// one message per line, e.g. to summarize the results of a CLI tool.
+// With a Formatter set (see SetFormatter) it instead writes a single entry
+// with a "rows" field: an array of objects keyed by header.
+func (l *Logger) InfofTable(headers []string, rows [][]string) {
+	infofTable(l, headers, rows)
+}
+
+// InfofTable writes headers and rows as an aligned text table at INFO level to
+// the root logger.
+// See Logger.InfofTable for details.
+func InfofTable(headers []string, rows [][]string) {
+	infofTable(Root, headers, rows)
+}
+
+// Shared by both forms of InfofTable, so the call depth is the same for each.
+func infofTable(l *Logger, headers []string, rows [][]string) {
+	if h, _ := l.shared.formatter.Load().(formatterHolder); h.Formatter != nil {
+		l.writeFields(LevelInfo, l.i, l.calldepth+1, Fields{"rows": tableObjects(headers, rows)}, "")
+		return
+	}
+	for _, line := range formatTable(headers, rows) {
+		l.write(LevelInfo, l.i, l.calldepth+1, "%s", line)
+	}
+}
+
+// Returns each row as a map from header to cell.
+// Cells beyond the headers are left out, and missing cells are empty.
+func tableObjects(headers []string, rows [][]string) []map[string]string {
+	objs := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		obj := make(map[string]string, len(headers))
+		for j, h := range headers {
+			if j < len(row) {
+				obj[h] = row[j]
+			} else {
+				obj[h] = ""
+			}
+		}
+		objs[i] = obj
+	}
+	return objs
+}
+
+// Returns the lines of the table, with columns padded to align.
+func formatTable(headers []string, rows [][]string) []string {
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	tw.Write([]byte(strings.Join(headers, "\t") + "\n"))
+	for _, row := range rows {
+		tw.Write([]byte(strings.Join(row, "\t") + "\n"))
+	}
+	tw.Flush()
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestInfofTable(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestInfofTable")
+	lg.Info = il
+
+	lg.InfofTable([]string{"NAME", "STATUS", "TIME"}, [][]string{
+		{"build", "ok", "1.2s"},
+		{"integration-tests", "failed", "31s"},
+	})
+
+	m := regexp.MustCompile(`^I.*table_test.go:\d+: NAME               STATUS  TIME
+I.*table_test.go:\d+: build              ok      1.2s
+I.*table_test.go:\d+: integration-tests  failed  31s
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
+
+func TestInfofTableFormatted(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestInfofTableFormatted")
+	lg.Info = il
+	lg.SetFormatter(JSONFormatter{})
+
+	lg.InfofTable([]string{"NAME", "STATUS"}, [][]string{
+		{"build", "ok"},
+		{"integration-tests", "failed"},
+	})
+
+	var got struct {
+		Fields struct {
+			Rows []map[string]string
+		}
+	}
+	if err := json.Unmarshal(il.Bytes(), &got); err != nil {
+		t.Fatalf("Got %v, want a single JSON entry from info log: %v", il.String(), err)
+	}
+	want := []map[string]string{
+		{"NAME": "build", "STATUS": "ok"},
+		{"NAME": "integration-tests", "STATUS": "failed"},
+	}
+	if !reflect.DeepEqual(got.Fields.Rows, want) {
+		t.Errorf("Got rows %v, want %v", got.Fields.Rows, want)
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// TeeWriter is an io.Writer that copies each write to every one of a set of
+// destinations, which may be added and removed while logging, e.g.
+//
+//	tee := log.NewTeeWriter(os.Stderr)
+//	log.Root.Info = tee
+//	...
+//	tee.Add(file)
+//
+// A failed destination does not keep the others from being written; Write
+// returns the errors of any that failed.
+// A TeeWriter is safe for concurrent use.
+type TeeWriter struct {
+	mu sync.RWMutex
+	ws []io.Writer
+}
+
+// NewTeeWriter returns a TeeWriter writing to ws.
+func NewTeeWriter(ws ...io.Writer) *TeeWriter {
+	return &TeeWriter{ws: append([]io.Writer(nil), ws...)}
+}
+
+// Add makes w a destination of later writes.
+func (t *TeeWriter) Add(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ws = append(t.ws[:len(t.ws):len(t.ws)], w)
+}
+
+// Remove stops later writes from going to w, if it is a destination.
+// Writes already in progress may still reach it.
+// A writer of a type that cannot be compared (like a func type) is never
+// found, so cannot be removed.
+func (t *TeeWriter) Remove(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ws := make([]io.Writer, 0, len(t.ws))
+	for _, d := range t.ws {
+		if !sameWriter(d, w) {
+			ws = append(ws, d)
+		}
+	}
+	t.ws = ws
+}
+
+// Write writes p to every destination.
+func (t *TeeWriter) Write(p []byte) (int, error) {
+	t.mu.RLock()
+	ws := t.ws
+	t.mu.RUnlock()
+
+	var errs []error
+	for _, w := range ws {
+		if _, err := w.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestTeeWriter(t *testing.T) {
+	a, b := new(bytes.Buffer), new(bytes.Buffer)
+	tee := NewTeeWriter(a, failingWriter{})
+	lg := New("TestTeeWriter")
+	lg.Info = tee
+
+	lg.Infof("Test message")
+	tee.Add(b)
+	lg.Infof("Test message")
+	tee.Remove(a)
+	tee.Remove(failingWriter{})
+	lg.Infof("Test message")
+
+	if got := strings.Count(a.String(), "Test message\n"); got != 2 {
+		t.Errorf("Got %v messages in the removed writer, want 2", got)
+	}
+	if got := strings.Count(b.String(), "Test message\n"); got != 2 {
+		t.Errorf("Got %v messages in the added writer, want 2", got)
+	}
+	if _, err := NewTeeWriter(failingWriter{}, a).Write([]byte("x")); err == nil {
+		t.Errorf("Got no error from Write, want one from the failed destination")
+	}
+}
+
+func TestTeeWriterConcurrent(t *testing.T) {
+	tee := NewTeeWriter()
+	lg := New("TestTeeWriterConcurrent")
+	lg.Info = tee
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			lg.Infof("Test message")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		b := new(lockedBuffer)
+		tee.Add(b)
+		tee.Remove(b)
+	}
+	wg.Wait()
+}
+
+// sliceWriter is a writer of a struct type that cannot be compared.
+type sliceWriter struct {
+	lines []string
+}
+
+func (w sliceWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestTeeWriterRemoveUncomparable(t *testing.T) {
+	b := new(bytes.Buffer)
+	tw := NewTeeWriter(sliceWriter{}, b)
+
+	tw.Remove(sliceWriter{})
+	tw.Remove(b)
+	tw.Write([]byte("Test message\n"))
+	if s := b.String(); len(s) > 0 {
+		t.Errorf("Got %v, want empty from a removed writer", s)
+	}
+}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// SetTimeFormat sets the layout (as for time.Format) of the timestamps in text
+// records, e.g. time.RFC3339Nano.
+// Combine it with UTC for timestamps that correlate across hosts.
+// An empty layout restores the default, which follows the standard log package
+// flags the logger was built with (e.g. "2006/01/02 15:04:05").
+func (l *Logger) SetTimeFormat(layout string) {
+	l.shared.timeFormat.Store(layout)
+}
+
+// Returns the default timestamp layout for the standard log package flags, or
+// "" if they call for no timestamp.
+func flagsLayout(flags int) string {
+	var parts []string
+	if flags&log.Ldate != 0 {
+		parts = append(parts, "2006/01/02")
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.Lmicroseconds != 0 {
+			parts = append(parts, "15:04:05.000000")
+		} else {
+			parts = append(parts, "15:04:05")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Renders text as the standard log package would with the given prefix and
+// flags, but with the logger's time format.
+// e supplies the time and caller.
+func (l *Logger) formatText(prefix string, flags int, e Entry, text string) []byte {
+	b := make([]byte, 0, len(prefix)+len(text)+64)
+	if flags&log.Lmsgprefix == 0 {
+		b = append(b, prefix...)
+	}
+	layout, _ := l.shared.timeFormat.Load().(string)
+	if layout == "" {
+		layout = flagsLayout(flags)
+	}
+	if l.OmitTime {
+		layout = ""
+	}
+	if layout != "" {
+		t := e.Time
+		if l.UTC || flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		b = t.AppendFormat(b, layout)
+		b = append(b, ' ')
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		file, line := e.File, e.Line
+		if file == "" {
+			file = "???"
+		}
+		b = append(b, file...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(line), 10)
+		b = append(b, ": "...)
+	}
+	if flags&log.Lmsgprefix != 0 {
+		b = append(b, prefix...)
+	}
+	b = append(b, text...)
+	if len(text) == 0 || text[len(text)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	return b
+}
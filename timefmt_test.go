@@ -0,0 +1,29 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestSetTimeFormat(t *testing.T) {
+	il := new(bytes.Buffer)
+	lg := New("TestSetTimeFormat")
+	lg.Info = il
+
+	lg.Infof("Test message")
+	lg.SetTimeFormat(time.RFC3339)
+	lg.UTC = true
+	lg.Infof("Test message")
+	lg.SetTimeFormat("")
+	lg.Infof("Test message")
+
+	m := regexp.MustCompile(`^I\d{4}/\d\d/\d\d \d\d:\d\d:\d\d timefmt_test.go:\d+: Test message
+I\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ timefmt_test.go:\d+: Test message
+I\d{4}/\d\d/\d\d \d\d:\d\d:\d\d timefmt_test.go:\d+: Test message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Bounds on how long a UnixSocketWriter waits between attempts to reconnect.
+const (
+	unixMinBackoff = 100 * time.Millisecond
+	unixMaxBackoff = 30 * time.Second
+)
+
+// UnixSocketWriter is an io.Writer that sends what is written to it to a Unix
+// domain socket, like that of a logging sidecar.
+// If the connection fails, it reconnects, waiting longer after each failed
+// attempt (up to 30 seconds); writes made while disconnected are dropped and
+// counted (see Dropped), rather than blocking the logger.
+// A UnixSocketWriter is safe for concurrent use.
+type UnixSocketWriter struct {
+	path string
+
+	mu         sync.Mutex
+	conn       net.Conn // Nil while disconnected.
+	closed     bool
+	dropped    int
+	retryAt    time.Time     // When to next try to reconnect.
+	backoff    time.Duration // How long to wait after the next failed attempt.
+	minBackoff time.Duration // Replaced in tests.
+}
+
+// NewUnixSocketWriter returns a UnixSocketWriter connected to the socket at
+// path, or an error if it cannot connect.
+func NewUnixSocketWriter(path string) (*UnixSocketWriter, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &UnixSocketWriter{
+		path:       path,
+		conn:       conn,
+		backoff:    unixMinBackoff,
+		minBackoff: unixMinBackoff,
+	}, nil
+}
+
+// Write sends p over the socket, reconnecting first if needed.
+// It always reports success unless the writer is closed, even if p is
+// dropped.
+func (w *UnixSocketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	// If the connection turns out to be broken, reconnect right away and try
+	// once more.
+	for try := 0; try < 2; try++ {
+		if w.conn == nil && !w.redial() {
+			break
+		}
+		if _, err := w.conn.Write(p); err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	w.dropped++
+	return len(p), nil
+}
+
+// Tries to reconnect, unless still backing off from a failed attempt.
+// w.mu must be held.
+func (w *UnixSocketWriter) redial() bool {
+	now := time.Now()
+	if now.Before(w.retryAt) {
+		return false
+	}
+	conn, err := net.Dial("unix", w.path)
+	if err != nil {
+		w.retryAt = now.Add(w.backoff)
+		if w.backoff *= 2; w.backoff > unixMaxBackoff {
+			w.backoff = unixMaxBackoff
+		}
+		return false
+	}
+	w.conn, w.backoff = conn, w.minBackoff
+	return true
+}
+
+// Dropped returns how many writes were dropped because the socket was
+// disconnected.
+func (w *UnixSocketWriter) Dropped() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// Close closes the connection. Later writes return ErrClosed.
+func (w *UnixSocketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Listens on the Unix socket at path, sending each line received to lines.
+// The returned func stops listening and drops the connections.
+func listenUnix(t *testing.T, path string, lines chan<- string) (stop func()) {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Listen(%v) failed: %v", path, err)
+	}
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go func() {
+				s := bufio.NewScanner(conn)
+				for s.Scan() {
+					lines <- s.Text()
+				}
+			}()
+		}
+	}()
+	return func() {
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+}
+
+func TestUnixSocketWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	lines := make(chan string, 100)
+	stop := listenUnix(t, path, lines)
+
+	w, err := NewUnixSocketWriter(path)
+	if err != nil {
+		t.Fatalf("NewUnixSocketWriter(%v) failed: %v", path, err)
+	}
+	defer w.Close()
+	w.minBackoff = time.Millisecond
+	lg := New("TestUnixSocketWriter")
+	lg.Info = w
+
+	lg.Infof("Before restart")
+	m := regexp.MustCompile(`^I.*unix_test.go:\d+: Before restart$`)
+	if got := <-lines; !m.MatchString(got) {
+		t.Errorf("Got %v, want something matching %v from the socket", got, m)
+	}
+
+	stop()
+	lg.Infof("While down")
+	if n := w.Dropped(); n != 1 {
+		t.Errorf("Got %v dropped, want 1 while the listener is down", n)
+	}
+
+	stop = listenUnix(t, path, lines)
+	defer stop()
+	m = regexp.MustCompile(`^I.*unix_test.go:\d+: After restart$`)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		lg.Infof("After restart")
+		select {
+		case got := <-lines:
+			if !m.MatchString(got) {
+				t.Errorf("Got %v, want something matching %v from the socket", got, m)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("Got nothing from the restarted listener, want a reconnect")
+}
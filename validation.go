@@ -0,0 +1,46 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WarnfValidation writes msg at WARN level with the validation errors as
+// structured fields: `count`, and `errors`, a JSON array of their messages.
+// Keeping each error separate keeps validation failures machine-readable,
+// where joining them would give one opaque string.
+func (l *Logger) WarnfValidation(msg string, errs []error) {
+	l.writeFields(LevelWarn, l.w, l.calldepth, validationFields(errs), "%s", msg)
+}
+
+// WarnfValidation writes msg at WARN level to the root logger, with the
+// validation errors as structured fields.
+// See Logger.WarnfValidation for details.
+func WarnfValidation(msg string, errs []error) {
+	Root.writeFields(LevelWarn, Root.w, Root.calldepth, validationFields(errs), "%s", msg)
+}
+
+// A list of error messages, which prints as a JSON array.
+type errorList []string
+
+func (e errorList) String() string {
+	b, err := json.Marshal([]string(e))
+	if err != nil {
+		return fmt.Sprint([]string(e))
+	}
+	return string(b)
+}
+
+// MarshalJSON encodes the list as a JSON array, for JSONFormatter.
+func (e errorList) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string(e))
+}
+
+// Returns the fields describing errs.
+func validationFields(errs []error) Fields {
+	list := make(errorList, len(errs))
+	for i, err := range errs {
+		list[i] = fmt.Sprint(err)
+	}
+	return Fields{"count": len(errs), "errors": list}
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestWarnfValidation(t *testing.T) {
+	wl := new(bytes.Buffer)
+	lg := New("TestWarnfValidation")
+	lg.Warn = wl
+
+	lg.WarnfValidation("Invalid signup form", []error{
+		errors.New("name is required"),
+		errors.New(`email "x" is malformed`),
+		errors.New("age must be positive"),
+	})
+
+	m := regexp.MustCompile(`^W.*validation_test.go:\d+: Invalid signup form count=3 errors=(\[.*\])\n$`)
+	s := wl.String()
+	sm := m.FindStringSubmatch(s)
+	if sm == nil {
+		t.Fatalf("Got %v, want something matching %v from warn log", s, m)
+	}
+	var got []string
+	if err := json.Unmarshal([]byte(sm[1]), &got); err != nil {
+		t.Fatalf("Got %v, want a JSON array of errors: %v", sm[1], err)
+	}
+	if len(got) != 3 || got[1] != `email "x" is malformed` {
+		t.Errorf("Got %q, want the three error messages", got)
+	}
+}
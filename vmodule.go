@@ -0,0 +1,101 @@
+package log
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// vmoduleFlag holds the per-name verbosity levels of the --vmodule flag, like
+// "cache=5,rpc=2".
+type vmoduleFlag struct {
+	mu     sync.RWMutex
+	levels map[string]int
+}
+
+var (
+	vmodule = new(vmoduleFlag)
+
+	// Loggers from Named, by name.
+	namedMu sync.Mutex
+	named   = make(map[string]*Logger)
+)
+
+func init() {
+	flag.Var(vmodule, "vmodule", "Comma-separated name=verbosity pairs, overriding --verbosity for loggers from log.Named.")
+}
+
+// String returns the levels in flag form, sorted by name.
+func (f *vmoduleFlag) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	pairs := make([]string, 0, len(f.levels))
+	for name, v := range f.levels {
+		pairs = append(pairs, name+"="+strconv.Itoa(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set replaces the levels with those parsed from s.
+func (f *vmoduleFlag) Set(s string) error {
+	levels := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		if pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return fmt.Errorf("invalid vmodule entry %q: want name=verbosity", pair)
+		}
+		v, err := strconv.Atoi(pair[i+1:])
+		if err != nil {
+			return fmt.Errorf("invalid vmodule entry %q: %v", pair, err)
+		}
+		levels[pair[:i]] = v
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.levels = levels
+	return nil
+}
+
+// Returns the verbosity set for name, if any.
+func (f *vmoduleFlag) level(name string) (int, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	v, ok := f.levels[name]
+	return v, ok
+}
+
+// Named returns the logger for the named subsystem, creating it on first use.
+// It writes through the root logger, but its verbosity is the one given for
+// name in the --vmodule flag (like "--vmodule=cache=5,rpc=2"), falling back to
+// the root logger's Verbosity if name is not listed.
+// The flag is consulted on every check, so Named may be called before flags are
+// parsed (e.g. in a package-level var).
+func Named(name string) *Logger {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if l, ok := named[name]; ok {
+		return l
+	}
+	l := Root.derive()
+	l.name = name
+	l.module = name
+	named[name] = l
+	return l
+}
+
+// Returns the logger's effective verbosity, before any BoostVerbosity.
+func (l *Logger) verbosity() int {
+	if l.module != "" {
+		if v, ok := vmodule.level(l.module); ok {
+			return v
+		}
+	}
+	return *l.Verbosity
+}
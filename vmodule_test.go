@@ -0,0 +1,44 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	il := new(bytes.Buffer)
+	Root.Info = il
+	defer func(v int) { *Verbosity = v }(*Verbosity)
+	*Verbosity = 1
+	defer vmodule.Set("")
+
+	cache, rpc := Named("cache"), Named("rpc")
+	if Named("cache") != cache {
+		t.Errorf("Got a new logger from the second Named(%q), want the cached one", "cache")
+	}
+	if err := vmodule.Set("cache=5,rpc=0"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, want := vmodule.String(), "cache=5,rpc=0"; got != want {
+		t.Errorf("Got %q, want %q from the vmodule flag", got, want)
+	}
+
+	cache.V(5, "Cache %s", "message")
+	rpc.V(1, "Dropped")
+	Named("other").V(1, "Other message")
+	Named("other").V(2, "Dropped")
+
+	m := regexp.MustCompile(`^I.*vmodule_test.go:\d+: Cache message
+I.*vmodule_test.go:\d+: Other message
+$`)
+	if s := il.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from info log", s, m)
+	}
+
+	for _, bad := range []string{"cache", "=3", "cache=loud"} {
+		if err := vmodule.Set(bad); err == nil {
+			t.Errorf("Got no error from Set(%q), want one", bad)
+		}
+	}
+}
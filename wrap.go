@@ -0,0 +1,35 @@
+package log
+
+import "fmt"
+
+// ErrorfWrap writes err, wrapped in the formatted context, at ERROR level, and
+// returns the wrapped error, so that an error can be logged and propagated in
+// one call:
+//
+//	if err := save(); err != nil {
+//	  return lg.ErrorfWrap(err, "saving %s", name)
+//	}
+//
+// The message and the returned error read like "saving ann: disk full", and
+// the returned error wraps err, for errors.Is and errors.As.
+// If err is nil, nothing is logged and nil is returned.
+func (l *Logger) ErrorfWrap(err error, format string, v ...interface{}) error {
+	return errorfWrap(l, err, format, v...)
+}
+
+// ErrorfWrap writes err, wrapped in the formatted context, at ERROR level to the
+// root logger, and returns the wrapped error.
+// See Logger.ErrorfWrap for details.
+func ErrorfWrap(err error, format string, v ...interface{}) error {
+	return errorfWrap(Root, err, format, v...)
+}
+
+// Shared by both forms of ErrorfWrap, so the call depth is the same for each.
+func errorfWrap(l *Logger, err error, format string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	wrapped := fmt.Errorf(format+": %w", append(v, err)...)
+	l.withStack(2).write(LevelError, l.e, l.calldepth+1, "%v", wrapped)
+	return wrapped
+}
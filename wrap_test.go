@@ -0,0 +1,33 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestErrorfWrap(t *testing.T) {
+	el := new(bytes.Buffer)
+	lg := New("TestErrorfWrap")
+	lg.Error = el
+
+	errDisk := errors.New("disk full")
+	err := lg.ErrorfWrap(fmt.Errorf("writing page %d: %w", 3, errDisk), "saving %s", "ann")
+	if !errors.Is(err, errDisk) {
+		t.Errorf("Got %v, want an error wrapping %v", err, errDisk)
+	}
+	if want := "saving ann: writing page 3: disk full"; err.Error() != want {
+		t.Errorf("Got error %q, want %q", err, want)
+	}
+	if err := lg.ErrorfWrap(nil, "saving %s", "bob"); err != nil {
+		t.Errorf("Got %v, want nil for a nil error", err)
+	}
+
+	m := regexp.MustCompile(`^E.*wrap_test.go:\d+: saving ann: writing page 3: disk full
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"io"
+	"strings"
+)
+
+// SetDebug makes w the Debug writer, safely even while other goroutines log.
+func (l *Logger) SetDebug(w io.Writer) { l.setWriter(LevelDebug, w) }
+
+// SetInfo makes w the Info writer, safely even while other goroutines log.
+func (l *Logger) SetInfo(w io.Writer) { l.setWriter(LevelInfo, w) }
+
+// SetWarn makes w the Warn writer, safely even while other goroutines log.
+func (l *Logger) SetWarn(w io.Writer) { l.setWriter(LevelWarn, w) }
+
+// SetError makes w the Error writer, safely even while other goroutines log.
+func (l *Logger) SetError(w io.Writer) { l.setWriter(LevelError, w) }
+
+// SetFatal makes w the Fatal writer, safely even while other goroutines log.
+func (l *Logger) SetFatal(w io.Writer) { l.setWriter(LevelFatal, w) }
+
+// SetAuditTrail makes w the AuditTrail writer, safely even while other
+// goroutines log.
+func (l *Logger) SetAuditTrail(w io.Writer) { l.setWriter(levelAudit, w) }
+
+// Replaces the writer for the given level under the writers lock.
+func (l *Logger) setWriter(level Level, w io.Writer) {
+	l.shared.writersMu.Lock()
+	defer l.shared.writersMu.Unlock()
+	*l.writerFor(level) = w
+}
+
+// LevelWriter returns an io.Writer that logs what is written to it at the given
+// level, for handing to libraries that log to a writer (like http.Server's
+// ErrorLog, through log.New).
+// Each line of a write becomes its own message; a trailing newline does not
+// add an empty one.
+// Writing at FATAL level does not call Exit, since the library doing the
+// writing does not expect that.
+// An unknown level is logged at ERROR level.
+func (l *Logger) LevelWriter(level Level) io.Writer {
+	if l.writerFor(level) == nil {
+		level = LevelError
+	}
+	return &levelWriter{l: l, level: level}
+}
+
+type levelWriter struct {
+	l     *Logger
+	level Level
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	lg := w.l.logable(w.level)
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		w.l.writeFields(w.level, lg, w.l.calldepth, nil, "%s", line)
+	}
+	return len(p), nil
+}
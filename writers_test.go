@@ -0,0 +1,102 @@
+package log
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetInfo(t *testing.T) {
+	a, b := new(lockedBuffer), new(lockedBuffer)
+	lg := New("TestSetInfo")
+	lg.SetInfo(a)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				lg.Infof("Test message")
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		if j%2 == 0 {
+			lg.SetInfo(b)
+		} else {
+			lg.SetInfo(a)
+		}
+	}
+	wg.Wait()
+
+	lines := strings.Count(a.String(), "Test message\n") + strings.Count(b.String(), "Test message\n")
+	if lines != 400 {
+		t.Errorf("Got %v messages across both writers, want 400", lines)
+	}
+}
+
+func TestLevelWriter(t *testing.T) {
+	wl, el := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestLevelWriter")
+	lg.Warn, lg.Error = wl, el
+
+	w := lg.LevelWriter(LevelWarn)
+	w.Write([]byte("First line\nSecond line\n"))
+	w.Write([]byte("No newline"))
+	log.New(lg.LevelWriter(Level(99)), "http: ", 0).Print("TLS handshake error")
+
+	m := regexp.MustCompile(`^W.*writers_test.go:\d+: First line
+W.*writers_test.go:\d+: Second line
+W.*writers_test.go:\d+: No newline
+$`)
+	if s := wl.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from warn log", s, m)
+	}
+	m = regexp.MustCompile(`^E.*: http: TLS handshake error
+$`)
+	if s := el.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from error log", s, m)
+	}
+}
+
+func TestSetInfoDerived(t *testing.T) {
+	a, b := new(bytes.Buffer), new(bytes.Buffer)
+	lg := New("TestSetInfoDerived")
+	lg.Info = a
+	c := lg.WithFields(Fields{"user": "ann"})
+
+	c.SetInfo(b)
+	c.Infof("Test message")
+	lg.Infof("Test message")
+	if a.Len() != 0 {
+		t.Errorf("Got %v from the old writer, want nothing after SetInfo on a derived logger", a)
+	}
+	m := regexp.MustCompile(`^I.*: Test message user=ann
+I.*: Test message
+$`)
+	if s := b.String(); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from the new writer", s, m)
+	}
+	if got := c.Config().Info; got != "buffer" {
+		t.Errorf("Got Config().Info %q, want %q", got, "buffer")
+	}
+}
+
+func TestConfigWhileSetting(t *testing.T) {
+	lg := New("TestConfigWhileSetting")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			lg.SetInfo(new(bytes.Buffer))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		lg.Config()
+	}
+	<-done
+}
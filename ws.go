@@ -0,0 +1,245 @@
+package log
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// wsGUID is the fixed key suffix from RFC 6455, section 1.3.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsQueue is how many lines may be waiting for a single client before that
+// client is considered too slow and dropped.
+const wsQueue = 64
+
+// WSHub is an io.Writer that broadcasts each written line to all connected
+// WebSocket clients.
+// Assign it to any of the Logger writers (e.g. `Root.Info = hub`) and serve
+// Handler() to let browsers watch the logs live.
+//
+// Clients that cannot keep up are disconnected rather than allowed to block the
+// logger.
+type WSHub struct {
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+}
+
+type wsClient struct {
+	conn net.Conn
+	send chan []byte
+	once sync.Once
+
+	// Guards w, which both serve and drain (replying to control frames)
+	// write to.
+	wmu sync.Mutex
+	w   *bufio.Writer
+}
+
+// Writes a single frame to the client.
+func (c *wsClient) write(opcode byte, p []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return writeWSFrame(c.w, opcode, p)
+}
+
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.send)
+		c.conn.Close()
+	})
+}
+
+// NewWSHub returns a WSHub with no connected clients.
+func NewWSHub() *WSHub {
+	return &WSHub{clients: make(map[*wsClient]struct{})}
+}
+
+// Write broadcasts p to every connected client, one text message per line.
+// It never blocks on a client, and never returns an error.
+func (h *WSHub) Write(p []byte) (int, error) {
+	lines := strings.Split(strings.TrimSuffix(string(p), "\n"), "\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+clients:
+	for c := range h.clients {
+		for _, line := range lines {
+			select {
+			case c.send <- []byte(line):
+			default:
+				delete(h.clients, c)
+				c.close()
+				continue clients
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// Close disconnects all clients.
+// The hub remains usable; new clients may still connect.
+func (h *WSHub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		delete(h.clients, c)
+		c.close()
+	}
+	return nil
+}
+
+// Handler returns an http.HandlerFunc that upgrades requests to WebSocket
+// connections and subscribes them to the hub.
+func (h *WSHub) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if key == "" || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			http.Error(w, "expected a websocket upgrade", http.StatusBadRequest)
+			return
+		}
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "websocket not supported", http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			return
+		}
+
+		c := &wsClient{conn: conn, send: make(chan []byte, wsQueue)}
+		// Register before completing the handshake, so anything logged after the
+		// client sees the response is delivered.
+		h.mu.Lock()
+		h.clients[c] = struct{}{}
+		h.mu.Unlock()
+
+		sum := sha1.Sum([]byte(key + wsGUID))
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+		if err := rw.Flush(); err != nil {
+			h.drop(c)
+			return
+		}
+
+		c.w = rw.Writer
+		go h.serve(c)
+		go h.drain(c, rw.Reader)
+	}
+}
+
+func (h *WSHub) drop(c *wsClient) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	c.close()
+}
+
+// serve writes queued lines to the client until it is dropped.
+func (h *WSHub) serve(c *wsClient) {
+	for line := range c.send {
+		if err := c.write(wsText, line); err != nil {
+			h.drop(c)
+			return
+		}
+	}
+}
+
+// drain reads client frames until the connection closes, or the client sends
+// a close frame (which is answered with one, as RFC 6455 requires).
+// Pings are answered with pongs, and anything else is ignored.
+func (h *WSHub) drain(c *wsClient, r *bufio.Reader) {
+	defer h.drop(c)
+	for {
+		opcode, p, err := readWSClientFrame(r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsClose:
+			// Echo the status code, if any, but not the reason.
+			if len(p) > 2 {
+				p = p[:2]
+			}
+			c.write(wsClose, p)
+			return
+		case wsPing:
+			c.write(wsPong, p)
+		}
+	}
+}
+
+// WebSocket opcodes, from RFC 6455, section 5.2.
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xa
+)
+
+// Reads a single frame from a client, returning its opcode and unmasked
+// payload.
+func readWSClientFrame(r *bufio.Reader) (byte, []byte, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var m uint16
+		if err := binary.Read(r, binary.BigEndian, &m); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(m)
+	case 127:
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return 0, nil, err
+		}
+	}
+	var mask [4]byte
+	if hdr[1]&0x80 != 0 {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	if n > 1<<20 {
+		// Clients have nothing to send a log stream; don't buffer floods.
+		return 0, nil, errors.New("websocket frame too large")
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(r, p); err != nil {
+		return 0, nil, err
+	}
+	for i := range p {
+		p[i] ^= mask[i%4]
+	}
+	return hdr[0] & 0x0f, p, nil
+}
+
+// writeWSFrame writes p as a single unmasked frame with the given opcode.
+func writeWSFrame(w *bufio.Writer, opcode byte, p []byte) error {
+	w.WriteByte(0x80 | opcode) // FIN + opcode.
+	switch n := len(p); {
+	case n < 126:
+		w.WriteByte(byte(n))
+	case n <= 0xffff:
+		w.WriteByte(126)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(127)
+		binary.Write(w, binary.BigEndian, uint64(n))
+	}
+	w.Write(p)
+	return w.Flush()
+}
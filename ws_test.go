@@ -0,0 +1,164 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readWSFrame reads a single unmasked frame, returning its opcode and payload.
+func readWSFrame(r *bufio.Reader) (byte, []byte, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	if hdr[1]&0x80 != 0 {
+		return 0, nil, errors.New("unexpected masked frame from server")
+	}
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var m uint16
+		if err := binary.Read(r, binary.BigEndian, &m); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(m)
+	case 127:
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return 0, nil, err
+		}
+	}
+	p := make([]byte, n)
+	_, err := io.ReadFull(r, p)
+	return hdr[0] & 0x0f, p, err
+}
+
+// dialWS connects a WebSocket client to srv, returning the connection and a
+// reader positioned after the handshake response.
+func dialWS(t *testing.T, srv *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Failed to dial %v: %v", srv.URL, err)
+	}
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		t.Fatalf("Failed to send handshake: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatalf("Failed to read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Got status %v, want %v", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	// The example accept value from RFC 6455, section 1.3.
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("Got accept key %v, want %v", got, want)
+	}
+	return conn, r
+}
+
+// Returns how many clients the hub has.
+func (h *WSHub) numClients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+func TestWSHub(t *testing.T) {
+	hub := NewWSHub()
+	defer hub.Close()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+	conn, r := dialWS(t, srv)
+	defer conn.Close()
+
+	lg := New("TestWSHub")
+	lg.Info = hub
+	lg.Infof("Test %s", "message")
+
+	_, p, err := readWSFrame(r)
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	m := regexp.MustCompile("^I.*Test message$")
+	if s := string(p); !m.MatchString(s) {
+		t.Errorf("Got %v, want something matching %v from the websocket", s, m)
+	}
+}
+
+func TestWSHubClientClose(t *testing.T) {
+	hub := NewWSHub()
+	defer hub.Close()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+	conn, r := dialWS(t, srv)
+	defer conn.Close()
+
+	// A masked close frame with status 1000 (normal closure).
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x88, 0x82, mask[0], mask[1], mask[2], mask[3], 0x03 ^ mask[0], 0xe8 ^ mask[1]}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("Failed to send close frame: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	opcode, p, err := readWSFrame(r)
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if opcode != 0x8 || string(p) != "\x03\xe8" {
+		t.Errorf("Got opcode %#x with %q, want a close frame with status 1000", opcode, p)
+	}
+	for i := 0; i < 100 && hub.numClients() > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := hub.numClients(); n != 0 {
+		t.Errorf("Got %v clients, want the closed one removed", n)
+	}
+}
+
+func TestWSHubSlowClient(t *testing.T) {
+	hub := NewWSHub()
+	defer hub.Close()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+	conn, _ := dialWS(t, srv)
+	defer conn.Close()
+
+	// The client never reads, so once the connection's buffers and the
+	// client's queue fill, it must be dropped rather than block the writer.
+	line := append(bytes.Repeat([]byte("x"), 64<<10), '\n')
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10000 && hub.numClients() > 0; i++ {
+			hub.Write(line)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Write blocked on a client that does not read")
+	}
+	if n := hub.numClients(); n != 0 {
+		t.Errorf("Got %v clients, want the slow one dropped", n)
+	}
+}